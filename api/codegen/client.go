@@ -0,0 +1,361 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//templateVarExp matches the variables of the path templates
+var templateVarExp = regexp.MustCompile(`{([^{}]+)}`)
+
+//clientResponse is a documented response of an operation decoded by the client
+type clientResponse struct {
+	//code is the status code, the range (2XX) or default
+	code string
+	//field of the response struct holding the decoded JSON body
+	field string
+	typ   string
+}
+
+//client declares the Client, the response structs and a method per operation
+func (g *generator) client(ops []*operation) error {
+	g.use("context")
+	g.use("net/http")
+	g.use("io/ioutil")
+	g.comment("Client calls the operations over HTTP.\n" +
+		"The responses are returned whatever their status code, the error is set only if the request fails or the JSON body cannot be decoded.")
+	g.line("type Client struct {")
+	g.comment("BaseURL is the URL the paths of the operations are relative to")
+	g.line("BaseURL string")
+	g.comment("HTTPClient sends the requests")
+	g.line("HTTPClient *http.Client")
+	g.line("}")
+	g.line("")
+	g.comment("NewClient creates a Client for the API served at the base URL, http.DefaultClient is used if httpClient is nil")
+	g.line("func NewClient(baseURL string, httpClient *http.Client) *Client {")
+	g.line("if httpClient == nil {")
+	g.line("httpClient = http.DefaultClient")
+	g.line("}")
+	g.line("return &Client{BaseURL: baseURL, HTTPClient: httpClient}")
+	g.line("}")
+	g.line("")
+	for _, o := range ops {
+		responses, err := g.clientResponses(o)
+		if err != nil {
+			return err
+		}
+		g.responseStruct(o, responses)
+		g.clientMethod(o, responses)
+	}
+	return nil
+}
+
+//resolveResponse follows the local response references
+func (g *generator) resolveResponse(r *spec.Response) (*spec.Response, error) {
+	if r == nil || r.Ref == nil {
+		return r, nil
+	}
+	name, ok := componentRef(*r.Ref, "responses")
+	if !ok || g.oas.Components == nil || g.oas.Components.Responses[name] == nil {
+		return nil, fmt.Errorf("unresolved response reference %s, bundle the document with spec.Bundle", *r.Ref)
+	}
+	return g.oas.Components.Responses[name], nil
+}
+
+//clientResponses collects the responses with a JSON body ordered by the status codes, the ranges and default
+func (g *generator) clientResponses(o *operation) ([]*clientResponse, error) {
+	codes := make([]string, 0, len(o.op.Responses))
+	for code := range o.op.Responses {
+		codes = append(codes, code)
+	}
+	rank := func(code string) int {
+		switch {
+		case code == "default":
+			return 2
+		case strings.HasSuffix(strings.ToUpper(code), "XX"):
+			return 1
+		}
+		return 0
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if rank(codes[i]) != rank(codes[j]) {
+			return rank(codes[i]) < rank(codes[j])
+		}
+		return codes[i] < codes[j]
+	})
+	var responses []*clientResponse
+	for _, code := range codes {
+		r, err := g.resolveResponse(o.op.Responses[code])
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			continue
+		}
+		schema, ok := jsonContent(r.Content)
+		if !ok {
+			continue
+		}
+		field := "JSON" + strings.ToUpper(code)
+		if code == "default" {
+			field = "JSONDefault"
+		}
+		responses = append(responses, &clientResponse{code: code, field: field, typ: g.goType(schema, o.name+field)})
+	}
+	return responses, nil
+}
+
+//responseStruct declares the struct returned by the client method of the operation
+func (g *generator) responseStruct(o *operation, responses []*clientResponse) {
+	g.comment(fmt.Sprintf("%sResponse is the response of %s", o.name, o.name))
+	g.line("type %sResponse struct {", o.name)
+	g.line("StatusCode int")
+	g.line("Header http.Header")
+	g.comment("Body is the raw body of the response")
+	g.line("Body []byte")
+	for _, r := range responses {
+		g.comment(fmt.Sprintf("%s is the decoded body of the %s response", r.field, r.code))
+		g.line("%s *%s", r.field, r.typ)
+	}
+	g.line("}")
+	g.line("")
+}
+
+//clientArgs are the arguments of the client method of the operation
+func (g *generator) clientArgs(o *operation) []string {
+	args := []string{"ctx context.Context"}
+	for _, p := range o.pathParams {
+		args = append(args, p.goName+" "+p.typ)
+	}
+	if len(o.params) > 0 {
+		args = append(args, "params "+o.name+"Params")
+	}
+	if o.body != nil {
+		if o.body.required {
+			args = append(args, "body "+o.body.typ)
+		} else {
+			args = append(args, "body *"+o.body.typ)
+		}
+	}
+	return args
+}
+
+//clientMethod declares the method of the Client calling the operation
+func (g *generator) clientMethod(o *operation, responses []*clientResponse) {
+	g.comment(fmt.Sprintf("%s calls %s %s", o.name, o.method, o.path))
+	if o.op.Summary != "" {
+		g.comment(o.op.Summary)
+	}
+	g.line("func (c *Client) %s(%s) (*%sResponse, error) {", o.name, strings.Join(g.clientArgs(o), ", "), o.name)
+	g.use("strings")
+	g.line("u := strings.TrimSuffix(c.BaseURL, \"/\") + %s", g.pathExpr(o))
+	if g.hasQuery(o) {
+		g.use("net/url")
+		g.line("q := url.Values{}")
+		for _, p := range o.params {
+			if p.spec.In == "query" {
+				g.encodeQuery(p)
+			}
+		}
+		g.line("if len(q) > 0 {")
+		g.line("u += \"?\" + q.Encode()")
+		g.line("}")
+	}
+	method := strconv.Quote(o.method)
+	switch {
+	case o.body == nil:
+		g.line("req, err := http.NewRequestWithContext(ctx, %s, u, nil)", method)
+	case o.body.required:
+		g.use("bytes")
+		g.use("encoding/json")
+		g.line("payload, err := json.Marshal(body)")
+		g.line("if err != nil {")
+		g.line("return nil, err")
+		g.line("}")
+		g.line("req, err := http.NewRequestWithContext(ctx, %s, u, bytes.NewReader(payload))", method)
+	default:
+		g.use("bytes")
+		g.use("encoding/json")
+		g.use("io")
+		g.line("var reader io.Reader")
+		g.line("if body != nil {")
+		g.line("payload, err := json.Marshal(body)")
+		g.line("if err != nil {")
+		g.line("return nil, err")
+		g.line("}")
+		g.line("reader = bytes.NewReader(payload)")
+		g.line("}")
+		g.line("req, err := http.NewRequestWithContext(ctx, %s, u, reader)", method)
+	}
+	g.line("if err != nil {")
+	g.line("return nil, err")
+	g.line("}")
+	if o.body != nil {
+		if !o.body.required {
+			g.line("if req.Body != nil {")
+		}
+		g.line("req.Header.Set(\"Content-Type\", \"application/json\")")
+		if !o.body.required {
+			g.line("}")
+		}
+	}
+	if len(responses) > 0 {
+		g.line("req.Header.Set(\"Accept\", \"application/json\")")
+	}
+	for _, p := range o.params {
+		switch p.spec.In {
+		case "header":
+			g.encodeHeaderOrCookie(p, "req.Header.Set(%s, %s)")
+		case "cookie":
+			g.encodeHeaderOrCookie(p, "req.AddCookie(&http.Cookie{Name: %s, Value: %s})")
+		}
+	}
+	g.line("resp, err := c.HTTPClient.Do(req)")
+	g.line("if err != nil {")
+	g.line("return nil, err")
+	g.line("}")
+	g.line("defer resp.Body.Close()")
+	g.line("data, err := ioutil.ReadAll(resp.Body)")
+	g.line("if err != nil {")
+	g.line("return nil, err")
+	g.line("}")
+	g.line("result := &%sResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}", o.name)
+	if len(responses) > 0 {
+		g.use("encoding/json")
+		g.line("if len(data) == 0 {")
+		g.line("return result, nil")
+		g.line("}")
+		g.line("switch {")
+		for _, r := range responses {
+			switch {
+			case r.code == "default":
+				g.line("default:")
+			case strings.HasSuffix(strings.ToUpper(r.code), "XX"):
+				g.line("case resp.StatusCode/100 == %s:", r.code[:1])
+			default:
+				g.line("case resp.StatusCode == %s:", r.code)
+			}
+			g.line("result.%s = new(%s)", r.field, r.typ)
+			g.line("if err = json.Unmarshal(data, result.%s); err != nil {", r.field)
+			g.line("return result, err")
+			g.line("}")
+		}
+		g.line("}")
+	}
+	g.line("return result, nil")
+	g.line("}")
+	g.line("")
+}
+
+//hasQuery checks if the operation has query parameters
+func (g *generator) hasQuery(o *operation) bool {
+	for _, p := range o.params {
+		if p.spec.In == "query" {
+			return true
+		}
+	}
+	return false
+}
+
+//pathExpr is the expression expanding the path template of the operation as per the styles of the path parameters
+func (g *generator) pathExpr(o *operation) string {
+	byName := make(map[string]*param)
+	for _, p := range o.pathParams {
+		byName[p.spec.Name] = p
+	}
+	var parts []string
+	last := 0
+	for _, loc := range templateVarExp.FindAllStringSubmatchIndex(o.path, -1) {
+		if loc[0] > last {
+			parts = append(parts, strconv.Quote(o.path[last:loc[0]]))
+		}
+		last = loc[1]
+		p := byName[o.path[loc[2]:loc[3]]]
+		if p == nil {
+			continue
+		}
+		g.use("net/url")
+		value := "url.PathEscape(" + g.format(p.goName, p.typ) + ")"
+		switch p.spec.StyleOrDefault() {
+		case spec.StyleLabel:
+			parts = append(parts, strconv.Quote("."), value)
+		case spec.StyleMatrix:
+			parts = append(parts, strconv.Quote(";"+p.spec.Name+"="), value)
+		default:
+			parts = append(parts, value)
+		}
+	}
+	if last < len(o.path) {
+		parts = append(parts, strconv.Quote(o.path[last:]))
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
+
+//format is the expression converting the value of the type to a string
+func (g *generator) format(value, typ string) string {
+	if typ == "string" {
+		return value
+	}
+	g.use("fmt")
+	return "fmt.Sprint(" + value + ")"
+}
+
+//joined is the expression joining the items of the array parameter with the delimiter
+func (g *generator) joined(p *param, field, delimiter string) string {
+	if p.typ != "string" {
+		g.line("values := make([]string, 0, len(%s))", field)
+		g.line("for _, v := range %s {", field)
+		g.line("values = append(values, %s)", g.format("v", p.typ))
+		g.line("}")
+		field = "values"
+	}
+	return "strings.Join(" + field + ", " + strconv.Quote(delimiter) + ")"
+}
+
+//encodeQuery adds the query parameter to the url.Values q as per its style and explode
+func (g *generator) encodeQuery(p *param) {
+	name := strconv.Quote(p.spec.Name)
+	field := "params." + p.goName
+	switch {
+	case p.array && p.spec.Exploded():
+		g.line("for _, v := range %s {", field)
+		g.line("q.Add(%s, %s)", name, g.format("v", p.typ))
+		g.line("}")
+	case p.array:
+		g.line("if len(%s) > 0 {", field)
+		g.line("q.Add(%s, %s)", name, g.joined(p, field, p.spec.Delimiter()))
+		g.line("}")
+	case p.spec.Required:
+		g.line("q.Add(%s, %s)", name, g.format(field, p.typ))
+	default:
+		g.line("if %s != nil {", field)
+		g.line("q.Add(%s, %s)", name, g.format("*"+field, p.typ))
+		g.line("}")
+	}
+}
+
+//encodeHeaderOrCookie sets the header or cookie parameter with the statement, the arrays are comma separated
+func (g *generator) encodeHeaderOrCookie(p *param, statement string) {
+	name := strconv.Quote(p.spec.Name)
+	field := "params." + p.goName
+	switch {
+	case p.array:
+		g.line("if len(%s) > 0 {", field)
+		g.line(statement, name, g.joined(p, field, ","))
+		g.line("}")
+	case p.spec.Required:
+		g.line(statement, name, g.format(field, p.typ))
+	default:
+		g.line("if %s != nil {", field)
+		g.line(statement, name, g.format("*"+field, p.typ))
+		g.line("}")
+	}
+}
@@ -0,0 +1,377 @@
+//Package codegen generates Go code from the OAS documents of the api/spec package.
+//
+//The generated file holds the models of the components/schemas and, as per the Options, a typed handler interface
+//with a Register function binding it on a turbo.Router and a typed HTTP client.
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//TurboImport is the import path of the turbo package used by the generated server code
+const TurboImport = "go.nandlabs.io/turbo"
+
+//Options for the code generation
+type Options struct {
+	//Package name of the generated file
+	Package string
+	//Server generates the Server interface of the operations and the Register function binding it on a turbo.Router
+	Server bool
+	//Client generates the Client calling the operations over HTTP
+	Client bool
+}
+
+//generator holds the state of a single generation
+type generator struct {
+	oas     *spec.OAS
+	imports map[string]bool
+	buf     bytes.Buffer
+	//types are the names of the declared types
+	types map[string]bool
+	//pending are the inline object schemas to be declared as named types
+	pending []*model
+}
+
+//model is a named type to be declared for a schema
+type model struct {
+	name   string
+	schema *spec.Schema
+}
+
+//Generate generates the formatted Go source for the OAS document
+func Generate(oas *spec.OAS, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, errors.New("package name is required")
+	}
+	g := &generator{
+		oas:     oas,
+		imports: make(map[string]bool),
+		types:   make(map[string]bool),
+	}
+	//the names of the component models are reserved before any inline type is named
+	if oas.Components != nil {
+		for name := range oas.Components.Schemas {
+			g.types[exportedName(name)] = true
+		}
+	}
+	ops, err := g.operations()
+	if err != nil {
+		return nil, err
+	}
+	g.models()
+	if opts.Server || opts.Client {
+		for _, o := range ops {
+			g.paramsStruct(o)
+		}
+	}
+	if opts.Server {
+		g.server(ops)
+	}
+	if opts.Client {
+		if err = g.client(ops); err != nil {
+			return nil, err
+		}
+	}
+	g.flush()
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by oasgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", opts.Package)
+	if len(g.imports) > 0 {
+		//standard library imports are grouped before the others
+		var std, others []string
+		for path := range g.imports {
+			if strings.Contains(strings.Split(path, "/")[0], ".") {
+				others = append(others, path)
+			} else {
+				std = append(std, path)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(others)
+		out.WriteString("import (\n")
+		for _, path := range std {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		if len(std) > 0 && len(others) > 0 {
+			out.WriteString("\n")
+		}
+		for _, path := range others {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.buf.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting the generated code: %w", err)
+	}
+	return src, nil
+}
+
+//line writes a line of code
+func (g *generator) line(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+//comment writes the text as a comment, one line of comment per line of text
+func (g *generator) comment(text string) {
+	for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+		g.line("// %s", strings.TrimSpace(l))
+	}
+}
+
+//use records an import of the generated file
+func (g *generator) use(path string) {
+	g.imports[path] = true
+}
+
+//componentRef returns the name of the component of the kind referred by the local reference
+func componentRef(ref, kind string) (string, bool) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(ref[len(prefix):]), true
+}
+
+//resolveSchema follows the local schema references
+func (g *generator) resolveSchema(s *spec.Schema) *spec.Schema {
+	for i := 0; s != nil && s.Ref != nil && i < 32; i++ {
+		name, ok := componentRef(*s.Ref, "schemas")
+		if !ok || g.oas.Components == nil {
+			return s
+		}
+		s = g.oas.Components.Schemas[name]
+	}
+	return s
+}
+
+//resolveParameter follows the local parameter references
+func (g *generator) resolveParameter(p *spec.Parameter) (*spec.Parameter, error) {
+	if p == nil || p.Ref == nil {
+		return p, nil
+	}
+	name, ok := componentRef(*p.Ref, "parameters")
+	if !ok || g.oas.Components == nil || g.oas.Components.Parameters[name] == nil {
+		return nil, fmt.Errorf("unresolved parameter reference %s, bundle the document with spec.Bundle", *p.Ref)
+	}
+	return g.oas.Components.Parameters[name], nil
+}
+
+//resolveRequestBody follows the local request body references
+func (g *generator) resolveRequestBody(b *spec.RequestBody) (*spec.RequestBody, error) {
+	if b == nil || b.Ref == nil {
+		return b, nil
+	}
+	name, ok := componentRef(*b.Ref, "requestBodies")
+	if !ok || g.oas.Components == nil || g.oas.Components.RequestBodies[name] == nil {
+		return nil, fmt.Errorf("unresolved request body reference %s, bundle the document with spec.Bundle", *b.Ref)
+	}
+	return g.oas.Components.RequestBodies[name], nil
+}
+
+//jsonContent returns the schema of the JSON media type of the content
+func jsonContent(content map[string]spec.MediaType) (*spec.Schema, bool) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		base := strings.TrimSpace(strings.Split(mediaType, ";")[0])
+		if base == "application/json" || strings.HasSuffix(base, "+json") {
+			return content[mediaType].Schema, true
+		}
+	}
+	return nil, false
+}
+
+//operation is an OAS operation with its resolved parameters
+type operation struct {
+	//name of the Go method
+	name   string
+	method string
+	path   string
+	op     *spec.Operation
+	//pathParams in the order of the path template
+	pathParams []*param
+	//params are the query, header and cookie parameters
+	params []*param
+	body   *body
+}
+
+//param is a parameter of an operation
+type param struct {
+	spec *spec.Parameter
+	//goName is the name of the Go field or argument
+	goName string
+	//typ is the Go type of the value, or of the items for arrays
+	typ   string
+	array bool
+}
+
+//body is the JSON request body of an operation
+type body struct {
+	typ      string
+	required bool
+}
+
+//operations collects the operations of the document ordered by path and method
+func (g *generator) operations() ([]*operation, error) {
+	paths := make([]string, 0, len(g.oas.Paths))
+	for path := range g.oas.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var ops []*operation
+	names := make(map[string]string)
+	for _, path := range paths {
+		item := g.oas.Paths[path]
+		if item == nil {
+			continue
+		}
+		all := item.Operations()
+		methods := make([]string, 0, len(all))
+		for method := range all {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			o := &operation{method: method, path: path, op: all[method]}
+			if o.op.OperationID != "" {
+				o.name = exportedName(o.op.OperationID)
+			} else {
+				o.name = exportedName(strings.ToLower(method) + " " + path)
+			}
+			if other, ok := names[o.name]; ok {
+				return nil, fmt.Errorf("%s %s and %s generate the same method name %s", method, path, other, o.name)
+			}
+			names[o.name] = method + " " + path
+			if err := g.parameters(o, item.Parameters); err != nil {
+				return nil, err
+			}
+			if err := g.requestBody(o); err != nil {
+				return nil, err
+			}
+			ops = append(ops, o)
+		}
+	}
+	return ops, nil
+}
+
+//parameters resolves the parameters of the operation, the operation parameters override the path item parameters
+func (g *generator) parameters(o *operation, common []spec.Parameter) error {
+	var all []*spec.Parameter
+	index := make(map[string]int)
+	add := func(p *spec.Parameter) error {
+		p, err := g.resolveParameter(p)
+		if err != nil || p == nil {
+			return err
+		}
+		key := p.In + ":" + p.Name
+		if i, ok := index[key]; ok {
+			all[i] = p
+		} else {
+			index[key] = len(all)
+			all = append(all, p)
+		}
+		return nil
+	}
+	for i := range common {
+		if err := add(&common[i]); err != nil {
+			return err
+		}
+	}
+	for _, p := range o.op.Parameters {
+		if err := add(p); err != nil {
+			return err
+		}
+	}
+	//the parameters of different locations generating the same field name are qualified with their location
+	counts := make(map[string]int)
+	for _, p := range all {
+		if p.In != "path" {
+			counts[exportedName(p.Name)]++
+		}
+	}
+	byName := make(map[string]*param)
+	fields := make(map[string]*spec.Parameter)
+	for _, p := range all {
+		typ, array := g.paramType(p)
+		if p.In == "path" {
+			byName[p.Name] = &param{spec: p, goName: pathParamName(p.Name), typ: typ, array: array}
+			continue
+		}
+		goName := exportedName(p.Name)
+		if counts[goName] > 1 {
+			goName = exportedName(p.In + " " + p.Name)
+		}
+		if other, ok := fields[goName]; ok {
+			return fmt.Errorf("parameters %s in %s and %s in %s of %s %s generate the same field name %s", other.Name,
+				other.In, p.Name, p.In, o.method, o.path, goName)
+		}
+		fields[goName] = p
+		o.params = append(o.params, &param{spec: p, goName: goName, typ: typ, array: array})
+	}
+	for _, segment := range strings.Split(o.path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := segment[1 : len(segment)-1]
+			prm, ok := byName[name]
+			if !ok {
+				prm = &param{spec: &spec.Parameter{Name: name, In: "path", Required: true}, goName: pathParamName(name), typ: "string"}
+			}
+			o.pathParams = append(o.pathParams, prm)
+		}
+	}
+	return nil
+}
+
+//paramType maps the schema of the parameter to a Go type
+func (g *generator) paramType(p *spec.Parameter) (string, bool) {
+	s := g.resolveSchema(p.Schema)
+	if s == nil {
+		return "string", false
+	}
+	if s.Type == spec.TypeArray {
+		return scalarType(g.resolveSchema(s.Items)), true
+	}
+	return scalarType(s), false
+}
+
+//scalarType maps the schema to string, int, float64 or bool
+func scalarType(s *spec.Schema) string {
+	if s != nil {
+		switch s.Type {
+		case spec.TypeInteger:
+			return "int"
+		case spec.TypeNumber:
+			return "float64"
+		case spec.TypeBoolean:
+			return "bool"
+		}
+	}
+	return "string"
+}
+
+//requestBody resolves the JSON request body of the operation
+func (g *generator) requestBody(o *operation) error {
+	b, err := g.resolveRequestBody(o.op.RequestBody)
+	if err != nil || b == nil {
+		return err
+	}
+	schema, ok := jsonContent(b.Content)
+	if !ok {
+		return nil
+	}
+	o.body = &body{typ: g.goType(schema, o.name+"RequestBody"), required: b.Required}
+	return nil
+}
@@ -0,0 +1,282 @@
+package codegen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	oas, err := spec.Bundle("internal/petstore/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Generate(oas, Options{Package: "petstore", Server: true, Client: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err := ioutil.ReadFile("internal/petstore/petstore.gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, existing) {
+		t.Error("internal/petstore/petstore.gen.go is stale, run go generate ./...")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	oas, err := spec.ParseFile("../spec/testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		opts    Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "models",
+			opts:    Options{Package: "api"},
+			want:    []string{"package api", "type Pet struct {", "ID   int64   `json:\"id\"`", "Tag  *string `json:\"tag,omitempty\"`"},
+			notWant: []string{"type Server interface", "go.nandlabs.io/turbo"},
+		},
+		{
+			name: "server",
+			opts: Options{Package: "api", Server: true},
+			want: []string{
+				"type ListPetsParams struct {",
+				"ShowPetByID(w http.ResponseWriter, r *http.Request, petID string)",
+				"CreatePet(w http.ResponseWriter, r *http.Request, body Pet)",
+				"func Register(router *turbo.Router, s Server) {",
+				`router.Add("/pets/{petId}", func(w http.ResponseWriter, r *http.Request) {`,
+			},
+			notWant: []string{"type Client struct"},
+		},
+		{
+			name: "client",
+			opts: Options{Package: "api", Client: true},
+			want: []string{
+				"type ListPetsParams struct {",
+				"func NewClient(baseURL string, httpClient *http.Client) *Client {",
+				"func (c *Client) ShowPetByID(ctx context.Context, petID string) (*ShowPetByIDResponse, error) {",
+				`u := strings.TrimSuffix(c.BaseURL, "/") + "/pets/" + url.PathEscape(petID)`,
+				"JSON200 *Pet",
+			},
+			notWant: []string{"type Server interface"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := Generate(oas, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("Generate() missing %s in\n%s", want, src)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(src), notWant) {
+					t.Errorf("Generate() unexpected %s in\n%s", notWant, src)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_ReservedPathParams(t *testing.T) {
+	oas, err := spec.Parse([]byte(`openapi: 3.1.0
+info: {title: Items, version: 1.0.0}
+paths:
+  /items/{err}/{r}:
+    get:
+      operationId: getItem
+      parameters:
+        - {name: r, in: path, required: true, schema: {type: string}}
+      responses: {'204': {description: ok}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Generate(oas, Options{Package: "api", Server: true, Client: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"GetItem(w http.ResponseWriter, r *http.Request, errParam string, rParam string)",
+		"func (c *Client) GetItem(ctx context.Context, errParam string, rParam string)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Generate() missing %s in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerate_ParamNames(t *testing.T) {
+	oas, err := spec.Parse([]byte(`openapi: 3.1.0
+info: {title: Items, version: 1.0.0}
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - {name: id, in: query, schema: {type: string}}
+        - {name: id, in: header, schema: {type: string}}
+        - {name: limit, in: query, schema: {type: integer}}
+      responses: {'204': {description: ok}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := Generate(oas, Options{Package: "api", Server: true, Client: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"params.QueryID = &raw", "params.HeaderID = &raw", "params.Limit = "} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Generate() missing %s in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	if _, err := Generate(&spec.OAS{}, Options{}); err == nil {
+		t.Error("Generate() expected an error without a package name")
+	}
+	ref := "other.yaml#/components/parameters/Limit"
+	limit := &spec.Parameter{}
+	limit.Ref = &ref
+	oas := &spec.OAS{Paths: map[string]*spec.PathItem{
+		"/pets": {Get: &spec.Operation{Parameters: []*spec.Parameter{limit}}},
+	}}
+	if _, err := Generate(oas, Options{Package: "api", Server: true}); err == nil {
+		t.Error("Generate() expected an error for an unresolved reference")
+	}
+	oas = &spec.OAS{Paths: map[string]*spec.PathItem{
+		"/a": {Get: &spec.Operation{OperationID: "pets"}},
+		"/b": {Get: &spec.Operation{OperationID: "Pets"}},
+	}}
+	if _, err := Generate(oas, Options{Package: "api", Server: true}); err == nil {
+		t.Error("Generate() expected an error for the clashing method names")
+	}
+	oas = &spec.OAS{Paths: map[string]*spec.PathItem{
+		"/a": {Get: &spec.Operation{Parameters: []*spec.Parameter{
+			{Name: "user_id", In: "query"},
+			{Name: "userId", In: "query"},
+		}}},
+	}}
+	if _, err := Generate(oas, Options{Package: "api", Server: true}); err == nil {
+		t.Error("Generate() expected an error for the clashing parameter names")
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		exported   string
+		unexported string
+	}{
+		{"petId", "PetID", "petID"},
+		{"X-Request-Id", "XRequestID", "xRequestID"},
+		{"list_pets", "ListPets", "listPets"},
+		{"HTTPServer", "HTTPServer", "httpServer"},
+		{"get /pets/{petId}", "GetPetsPetID", "getPetsPetID"},
+		{"type", "Type", "typeValue"},
+		{"2fa", "N2fa", "n2fa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportedName(tt.name); got != tt.exported {
+				t.Errorf("exportedName() = %v, want %v", got, tt.exported)
+			}
+			if got := unexportedName(tt.name); got != tt.unexported {
+				t.Errorf("unexportedName() = %v, want %v", got, tt.unexported)
+			}
+		})
+	}
+}
+
+func TestPathExpr(t *testing.T) {
+	explode := true
+	tests := []struct {
+		name  string
+		param spec.Parameter
+		want  string
+	}{
+		{
+			name:  "simple",
+			param: spec.Parameter{Name: "id", In: "path", Schema: &spec.Schema{Type: spec.TypeInteger}},
+			want:  `"/items/" + url.PathEscape(fmt.Sprint(id)) + "/tags"`,
+		},
+		{
+			name:  "label",
+			param: spec.Parameter{Name: "id", In: "path", Style: spec.StyleLabel, Schema: &spec.Schema{Type: spec.TypeString}},
+			want:  `"/items/" + "." + url.PathEscape(id) + "/tags"`,
+		},
+		{
+			name:  "matrix",
+			param: spec.Parameter{Name: "id", In: "path", Style: spec.StyleMatrix, Explode: &explode, Schema: &spec.Schema{Type: spec.TypeString}},
+			want:  `"/items/" + ";id=" + url.PathEscape(id) + "/tags"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &generator{oas: &spec.OAS{}, imports: make(map[string]bool), types: make(map[string]bool)}
+			o := &operation{path: "/items/{id}/tags", op: &spec.Operation{Parameters: []*spec.Parameter{&tt.param}}}
+			if err := g.parameters(o, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := g.pathExpr(o); got != tt.want {
+				t.Errorf("pathExpr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQuery(t *testing.T) {
+	explode := false
+	tests := []struct {
+		name  string
+		param spec.Parameter
+		want  string
+	}{
+		{
+			name:  "exploded form",
+			param: spec.Parameter{Name: "ids", In: "query", Schema: &spec.Schema{Type: spec.TypeArray, Items: &spec.Schema{Type: spec.TypeInteger}}},
+			want:  `q.Add("ids", fmt.Sprint(v))`,
+		},
+		{
+			name:  "form",
+			param: spec.Parameter{Name: "ids", In: "query", Explode: &explode, Schema: &spec.Schema{Type: spec.TypeArray, Items: &spec.Schema{Type: spec.TypeString}}},
+			want:  `q.Add("ids", strings.Join(params.Ids, ","))`,
+		},
+		{
+			name:  "pipe delimited",
+			param: spec.Parameter{Name: "ids", In: "query", Style: spec.StylePipeDelimited, Explode: &explode, Schema: &spec.Schema{Type: spec.TypeArray, Items: &spec.Schema{Type: spec.TypeString}}},
+			want:  `q.Add("ids", strings.Join(params.Ids, "|"))`,
+		},
+		{
+			name:  "required",
+			param: spec.Parameter{Name: "ids", In: "query", Required: true, Schema: &spec.Schema{Type: spec.TypeString}},
+			want:  `q.Add("ids", params.Ids)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &generator{oas: &spec.OAS{}, imports: make(map[string]bool), types: make(map[string]bool)}
+			o := &operation{path: "/items", op: &spec.Operation{Parameters: []*spec.Parameter{&tt.param}}}
+			if err := g.parameters(o, nil); err != nil {
+				t.Fatal(err)
+			}
+			g.encodeQuery(o.params[0])
+			if got := g.buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("encodeQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
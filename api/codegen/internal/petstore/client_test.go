@@ -0,0 +1,90 @@
+package petstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nandlabs.io/turbo"
+)
+
+func newTestClient(t *testing.T) (*Client, *store) {
+	s := &store{}
+	router := turbo.NewRouter()
+	Register(router, s)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/", server.Client()), s
+}
+
+func TestClient_ListPets(t *testing.T) {
+	client, s := newTestClient(t)
+	limit := 5
+	resp, err := client.ListPets(context.Background(), ListPetsParams{
+		Limit:      &limit,
+		Tags:       []string{"a", "b"},
+		Ids:        []int{1, 2},
+		XRequestID: "abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.JSON200 == nil || len(*resp.JSON200) != 1 || (*resp.JSON200)[0].Name != "rex" {
+		t.Fatalf("ListPets() = %+v", resp)
+	}
+	if *s.params.Limit != 5 || strings.Join(s.params.Tags, "|") != "a|b" || len(s.params.Ids) != 2 || s.params.Ids[1] != 2 || s.params.XRequestID != "abc" {
+		t.Errorf("ListPets() decoded params = %+v", s.params)
+	}
+}
+
+func TestClient_CreatePet(t *testing.T) {
+	client, s := newTestClient(t)
+	status := StatusPending
+	resp, err := client.CreatePet(context.Background(), NewPet{Name: "rex", Status: &status})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.JSON201 == nil || resp.JSON201.ID != 2 || resp.JSON201.Name != "rex" {
+		t.Fatalf("CreatePet() = %+v", resp)
+	}
+	if pet := s.body.(NewPet); *pet.Status != StatusPending {
+		t.Errorf("CreatePet() decoded body = %+v", pet)
+	}
+}
+
+func TestClient_ShowPetByID(t *testing.T) {
+	client, s := newTestClient(t)
+	resp, err := client.ShowPetByID(context.Background(), 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || s.petID != 42 {
+		t.Fatalf("ShowPetByID() = %+v, petID = %v", resp, s.petID)
+	}
+	resp, err = client.ShowPetByID(context.Background(), http.StatusNotFound)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound || resp.JSONDefault == nil || resp.JSONDefault.Message != "pet not found" {
+		t.Errorf("ShowPetByID() = %+v", resp)
+	}
+}
+
+func TestClient_UpdatePet(t *testing.T) {
+	client, s := newTestClient(t)
+	name := "max"
+	if _, err := client.UpdatePet(context.Background(), 7, &UpdatePetRequestBody{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if body := s.body.(*UpdatePetRequestBody); s.petID != 7 || body == nil || *body.Name != "max" {
+		t.Errorf("UpdatePet() decoded body = %+v", s.body)
+	}
+	if _, err := client.UpdatePet(context.Background(), 7, nil); err != nil {
+		t.Fatal(err)
+	}
+	if body := s.body.(*UpdatePetRequestBody); body != nil {
+		t.Errorf("UpdatePet() decoded body = %+v, want nil", body)
+	}
+}
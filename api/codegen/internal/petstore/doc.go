@@ -0,0 +1,4 @@
+//Package petstore is generated from openapi.yaml, it verifies that the generated code builds and serves the requests.
+package petstore
+
+//go:generate go run go.nandlabs.io/turbo/cmd/oasgen -spec openapi.yaml -package petstore -client -o petstore.gen.go
@@ -0,0 +1,378 @@
+// Code generated by oasgen. DO NOT EDIT.
+
+package petstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.nandlabs.io/turbo"
+)
+
+// Error is the Error schema
+type Error struct {
+	Code    int32             `json:"code"`
+	Details map[string]string `json:"details,omitempty"`
+	Message string            `json:"message"`
+}
+
+// NewPet is the NewPet schema
+type NewPet struct {
+	Name   string       `json:"name"`
+	Owner  *NewPetOwner `json:"owner,omitempty"`
+	Status *Status      `json:"status,omitempty"`
+	Tag    *string      `json:"tag,omitempty"`
+}
+
+// Pet is the Pet schema
+// A pet of the store
+type Pet struct {
+	NewPet
+	ID int64 `json:"id"`
+}
+
+// Pets is the Pets schema
+type Pets []Pet
+
+// Status is the Status schema
+type Status string
+
+const (
+	StatusAvailable Status = "available"
+	StatusPending   Status = "pending"
+	StatusSold      Status = "sold"
+)
+
+// UpdatePetRequestBody is an inline schema
+type UpdatePetRequestBody struct {
+	Name   *string `json:"name,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// NewPetOwner is an inline schema
+type NewPetOwner struct {
+	Email *string `json:"email,omitempty"`
+	Name  *string `json:"name,omitempty"`
+}
+
+// ListPetsParams are the parameters of ListPets
+type ListPetsParams struct {
+	// How many items to return at one time
+	Limit      *int
+	Tags       []string
+	Ids        []int
+	XRequestID string
+}
+
+// Server is implemented by the handlers of the operations.
+// The path parameters, the query, header and cookie parameters and the JSON request body are decoded before the handler is invoked.
+type Server interface {
+	// ListPets handles GET /pets
+	// List all pets
+	ListPets(w http.ResponseWriter, r *http.Request, params ListPetsParams)
+	// CreatePet handles POST /pets
+	CreatePet(w http.ResponseWriter, r *http.Request, body NewPet)
+	// ShowPetByID handles GET /pets/{petId}
+	ShowPetByID(w http.ResponseWriter, r *http.Request, petID int)
+	// UpdatePet handles PATCH /pets/{petId}
+	UpdatePet(w http.ResponseWriter, r *http.Request, petID int, body *UpdatePetRequestBody)
+}
+
+// Register binds the operations of the Server on the router.
+// Requests with missing or malformed parameters or request body are rejected with 400 Bad Request.
+func Register(router *turbo.Router, s Server) {
+	router.Add("/pets", func(w http.ResponseWriter, r *http.Request) {
+		var params ListPetsParams
+		if values, ok := r.URL.Query()["limit"]; ok {
+			raw := values[0]
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				http.Error(w, "invalid query parameter limit", http.StatusBadRequest)
+				return
+			}
+			params.Limit = &v
+		}
+		if values, ok := r.URL.Query()["tags"]; ok {
+			if len(values) == 1 {
+				values = strings.Split(values[0], ",")
+			}
+			for _, raw := range values {
+				params.Tags = append(params.Tags, raw)
+			}
+		}
+		if values, ok := r.URL.Query()["ids"]; ok {
+			for _, raw := range values {
+				v, err := strconv.Atoi(raw)
+				if err != nil {
+					http.Error(w, "invalid query parameter ids", http.StatusBadRequest)
+					return
+				}
+				params.Ids = append(params.Ids, v)
+			}
+		}
+		if values, ok := r.Header[http.CanonicalHeaderKey("X-Request-Id")]; ok {
+			raw := values[0]
+			params.XRequestID = raw
+		} else {
+			http.Error(w, "missing header parameter X-Request-Id", http.StatusBadRequest)
+			return
+		}
+		s.ListPets(w, r, params)
+	}, turbo.GET)
+	router.Add("/pets", func(w http.ResponseWriter, r *http.Request) {
+		var body NewPet
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.CreatePet(w, r, body)
+	}, turbo.POST)
+	router.Add("/pets/{petId}", func(w http.ResponseWriter, r *http.Request) {
+		petID, err := router.GetIntPathParams("petId", r)
+		if err != nil {
+			http.Error(w, "invalid path parameter petId", http.StatusBadRequest)
+			return
+		}
+		s.ShowPetByID(w, r, petID)
+	}, turbo.GET)
+	router.Add("/pets/{petId}", func(w http.ResponseWriter, r *http.Request) {
+		petID, err := router.GetIntPathParams("petId", r)
+		if err != nil {
+			http.Error(w, "invalid path parameter petId", http.StatusBadRequest)
+			return
+		}
+		var body *UpdatePetRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.UpdatePet(w, r, petID, body)
+	}, turbo.PATCH)
+}
+
+// Client calls the operations over HTTP.
+// The responses are returned whatever their status code, the error is set only if the request fails or the JSON body cannot be decoded.
+type Client struct {
+	// BaseURL is the URL the paths of the operations are relative to
+	BaseURL string
+	// HTTPClient sends the requests
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client for the API served at the base URL, http.DefaultClient is used if httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{BaseURL: baseURL, HTTPClient: httpClient}
+}
+
+// ListPetsResponse is the response of ListPets
+type ListPetsResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the decoded body of the 200 response
+	JSON200 *Pets
+}
+
+// ListPets calls GET /pets
+// List all pets
+func (c *Client) ListPets(ctx context.Context, params ListPetsParams) (*ListPetsResponse, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + "/pets"
+	q := url.Values{}
+	if params.Limit != nil {
+		q.Add("limit", fmt.Sprint(*params.Limit))
+	}
+	if len(params.Tags) > 0 {
+		q.Add("tags", strings.Join(params.Tags, ","))
+	}
+	for _, v := range params.Ids {
+		q.Add("ids", fmt.Sprint(v))
+	}
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Request-Id", params.XRequestID)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &ListPetsResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	if len(data) == 0 {
+		return result, nil
+	}
+	switch {
+	case resp.StatusCode == 200:
+		result.JSON200 = new(Pets)
+		if err = json.Unmarshal(data, result.JSON200); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// CreatePetResponse is the response of CreatePet
+type CreatePetResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON201 is the decoded body of the 201 response
+	JSON201 *Pet
+}
+
+// CreatePet calls POST /pets
+func (c *Client) CreatePet(ctx context.Context, body NewPet) (*CreatePetResponse, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + "/pets"
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", u, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &CreatePetResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	if len(data) == 0 {
+		return result, nil
+	}
+	switch {
+	case resp.StatusCode == 201:
+		result.JSON201 = new(Pet)
+		if err = json.Unmarshal(data, result.JSON201); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ShowPetByIDResponse is the response of ShowPetByID
+type ShowPetByIDResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the decoded body of the 200 response
+	JSON200 *Pet
+	// JSONDefault is the decoded body of the default response
+	JSONDefault *Error
+}
+
+// ShowPetByID calls GET /pets/{petId}
+func (c *Client) ShowPetByID(ctx context.Context, petID int) (*ShowPetByIDResponse, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + "/pets/" + url.PathEscape(fmt.Sprint(petID))
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &ShowPetByIDResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	if len(data) == 0 {
+		return result, nil
+	}
+	switch {
+	case resp.StatusCode == 200:
+		result.JSON200 = new(Pet)
+		if err = json.Unmarshal(data, result.JSON200); err != nil {
+			return result, err
+		}
+	default:
+		result.JSONDefault = new(Error)
+		if err = json.Unmarshal(data, result.JSONDefault); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// UpdatePetResponse is the response of UpdatePet
+type UpdatePetResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the raw body of the response
+	Body []byte
+	// JSON200 is the decoded body of the 200 response
+	JSON200 *Pet
+}
+
+// UpdatePet calls PATCH /pets/{petId}
+func (c *Client) UpdatePet(ctx context.Context, petID int, body *UpdatePetRequestBody) (*UpdatePetResponse, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + "/pets/" + url.PathEscape(fmt.Sprint(petID))
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &UpdatePetResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+	if len(data) == 0 {
+		return result, nil
+	}
+	switch {
+	case resp.StatusCode == 200:
+		result.JSON200 = new(Pet)
+		if err = json.Unmarshal(data, result.JSON200); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
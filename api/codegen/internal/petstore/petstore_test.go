@@ -0,0 +1,162 @@
+package petstore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nandlabs.io/turbo"
+)
+
+//store implements the generated Server recording the decoded arguments
+type store struct {
+	params ListPetsParams
+	petID  int
+	body   interface{}
+}
+
+func (s *store) ListPets(w http.ResponseWriter, r *http.Request, params ListPetsParams) {
+	s.params = params
+	_ = json.NewEncoder(w).Encode(Pets{{ID: 1, NewPet: NewPet{Name: "rex"}}})
+}
+
+func (s *store) CreatePet(w http.ResponseWriter, r *http.Request, body NewPet) {
+	s.body = body
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(Pet{ID: 2, NewPet: body})
+}
+
+func (s *store) ShowPetByID(w http.ResponseWriter, r *http.Request, petID int) {
+	s.petID = petID
+	if petID == http.StatusNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(Error{Code: 404, Message: "pet not found"})
+	}
+}
+
+func (s *store) UpdatePet(w http.ResponseWriter, r *http.Request, petID int, body *UpdatePetRequestBody) {
+	s.petID = petID
+	s.body = body
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		header map[string]string
+		body   string
+		status int
+		check  func(t *testing.T, s *store)
+	}{
+		{
+			name:   "query and header params",
+			method: http.MethodGet,
+			url:    "/pets?limit=10&tags=a,b",
+			header: map[string]string{"X-Request-Id": "abc"},
+			status: http.StatusOK,
+			check: func(t *testing.T, s *store) {
+				if s.params.Limit == nil || *s.params.Limit != 10 {
+					t.Errorf("Limit = %v, want 10", s.params.Limit)
+				}
+				if strings.Join(s.params.Tags, "|") != "a|b" || s.params.XRequestID != "abc" {
+					t.Errorf("params = %+v", s.params)
+				}
+			},
+		},
+		{
+			name:   "optional query param",
+			method: http.MethodGet,
+			url:    "/pets",
+			header: map[string]string{"X-Request-Id": "abc"},
+			status: http.StatusOK,
+			check: func(t *testing.T, s *store) {
+				if s.params.Limit != nil {
+					t.Errorf("Limit = %v, want nil", *s.params.Limit)
+				}
+			},
+		},
+		{
+			name:   "missing required header",
+			method: http.MethodGet,
+			url:    "/pets",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "malformed query param",
+			method: http.MethodGet,
+			url:    "/pets?limit=ten",
+			header: map[string]string{"X-Request-Id": "abc"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "path param",
+			method: http.MethodGet,
+			url:    "/pets/42",
+			status: http.StatusOK,
+			check: func(t *testing.T, s *store) {
+				if s.petID != 42 {
+					t.Errorf("petID = %v, want 42", s.petID)
+				}
+			},
+		},
+		{
+			name:   "malformed path param",
+			method: http.MethodGet,
+			url:    "/pets/rex",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "request body",
+			method: http.MethodPost,
+			url:    "/pets",
+			body:   `{"name":"rex","status":"available","owner":{"name":"jo"}}`,
+			status: http.StatusCreated,
+			check: func(t *testing.T, s *store) {
+				pet, ok := s.body.(NewPet)
+				if !ok || pet.Name != "rex" || pet.Status == nil || *pet.Status != StatusAvailable || *pet.Owner.Name != "jo" {
+					t.Errorf("body = %+v", s.body)
+				}
+			},
+		},
+		{
+			name:   "malformed request body",
+			method: http.MethodPost,
+			url:    "/pets",
+			body:   `{"name":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "optional request body",
+			method: http.MethodPatch,
+			url:    "/pets/7",
+			status: http.StatusOK,
+			check: func(t *testing.T, s *store) {
+				if body, ok := s.body.(*UpdatePetRequestBody); !ok || body != nil || s.petID != 7 {
+					t.Errorf("body = %+v, petID = %v", s.body, s.petID)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &store{}
+			router := turbo.NewRouter()
+			Register(router, s)
+			r := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t, s)
+			}
+		})
+	}
+}
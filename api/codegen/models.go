@@ -0,0 +1,214 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//models declares a type for every schema of the components
+func (g *generator) models() {
+	if g.oas.Components == nil {
+		g.flush()
+		return
+	}
+	names := make([]string, 0, len(g.oas.Components.Schemas))
+	for name := range g.oas.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.model(exportedName(name), name, g.oas.Components.Schemas[name])
+	}
+	g.flush()
+}
+
+//flush declares the pending inline object types
+func (g *generator) flush() {
+	for len(g.pending) > 0 {
+		m := g.pending[0]
+		g.pending = g.pending[1:]
+		g.model(m.name, "", m.schema)
+	}
+}
+
+//model declares the named type for the schema
+func (g *generator) model(name, component string, s *spec.Schema) {
+	if s == nil {
+		s = &spec.Schema{}
+	}
+	if component != "" {
+		g.comment(fmt.Sprintf("%s is the %s schema", name, component))
+	} else {
+		g.comment(name + " is an inline schema")
+	}
+	if s.Description != "" {
+		g.comment(s.Description)
+	}
+	if isStruct(s) {
+		g.structType(name, s)
+		return
+	}
+	if s.Ref == nil && s.Type == spec.TypeString && len(s.Enum) > 0 {
+		g.enumType(name, s)
+		return
+	}
+	g.line("type %s %s", name, g.goType(s, name+"Item"))
+	g.line("")
+}
+
+//structType declares the struct for the object schema, the allOf references are embedded
+func (g *generator) structType(name string, s *spec.Schema) {
+	properties := make(map[string]*spec.Schema)
+	required := make(map[string]bool)
+	var embedded []string
+	var merge func(s *spec.Schema)
+	merge = func(s *spec.Schema) {
+		for p, ps := range s.Properties {
+			properties[p] = ps
+		}
+		for _, r := range s.Required {
+			required[r] = true
+		}
+		for _, part := range s.AllOf {
+			if part == nil {
+				continue
+			}
+			if part.Ref != nil {
+				embedded = append(embedded, g.goType(part, ""))
+			} else {
+				merge(part)
+			}
+		}
+	}
+	merge(s)
+	keys := make([]string, 0, len(properties))
+	for p := range properties {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+	g.line("type %s struct {", name)
+	for _, e := range embedded {
+		g.line("%s", e)
+	}
+	for _, p := range keys {
+		ps := properties[p]
+		field := exportedName(p)
+		typ := g.goType(ps, name+field)
+		tag := p
+		if !required[p] {
+			tag += ",omitempty"
+		}
+		if (!required[p] || (ps != nil && ps.Nullable)) && g.nilable(ps, typ) {
+			typ = "*" + typ
+		}
+		if ps != nil && ps.Description != "" {
+			g.comment(ps.Description)
+		}
+		g.line("%s %s `json:%s`", field, typ, strconv.Quote(tag))
+	}
+	g.line("}")
+	g.line("")
+}
+
+//enumType declares a string type along with a constant for every value of the enum
+func (g *generator) enumType(name string, s *spec.Schema) {
+	g.line("type %s string", name)
+	g.line("")
+	g.line("const (")
+	for _, v := range s.Enum {
+		value := fmt.Sprint(v)
+		g.line("%s%s %s = %s", name, exportedName(value), name, strconv.Quote(value))
+	}
+	g.line(")")
+	g.line("")
+}
+
+//isStruct checks if the schema is an object with properties
+func isStruct(s *spec.Schema) bool {
+	return s.Ref == nil && (s.Type == spec.TypeObject || s.Type == "") && (len(s.Properties) > 0 || len(s.AllOf) > 0)
+}
+
+//goType maps the schema to a Go type, the inline objects are declared as named types with the hint as their name
+func (g *generator) goType(s *spec.Schema, hint string) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if s.Ref != nil {
+		if name, ok := componentRef(*s.Ref, "schemas"); ok {
+			return exportedName(name)
+		}
+		return "interface{}"
+	}
+	switch s.Type {
+	case spec.TypeString:
+		return "string"
+	case spec.TypeInteger:
+		if s.Format != nil && *s.Format == "int64" {
+			return "int64"
+		}
+		if s.Format != nil && *s.Format == "int32" {
+			return "int32"
+		}
+		return "int"
+	case spec.TypeNumber:
+		if s.Format != nil && *s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case spec.TypeBoolean:
+		return "bool"
+	case spec.TypeArray:
+		return "[]" + g.goType(s.Items, hint+"Item")
+	}
+	if isStruct(s) {
+		name := hint
+		for i := 2; g.types[name]; i++ {
+			name = fmt.Sprintf("%s%d", hint, i)
+		}
+		g.types[name] = true
+		g.pending = append(g.pending, &model{name: name, schema: s})
+		return name
+	}
+	if s.Type == spec.TypeObject {
+		if additional := additionalProperties(s); additional != nil {
+			return "map[string]" + g.goType(additional, hint+"Value")
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+//additionalProperties returns the schema of the additional properties, nil if they are not constrained
+func additionalProperties(s *spec.Schema) *spec.Schema {
+	switch v := s.AdditionalProperties.(type) {
+	case *spec.Schema:
+		return v
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		additional := &spec.Schema{}
+		if json.Unmarshal(data, additional) != nil {
+			return nil
+		}
+		return additional
+	}
+	return nil
+}
+
+//nilable checks if the Go type of an optional field should be a pointer, slices, maps and interfaces are already nil
+func (g *generator) nilable(s *spec.Schema, typ string) bool {
+	if typ == "interface{}" || typ[0] == '[' || len(typ) > 3 && typ[:4] == "map[" {
+		return false
+	}
+	if s != nil && s.Ref != nil {
+		resolved := g.resolveSchema(s)
+		return resolved == nil || isStruct(resolved) || (resolved.Type != spec.TypeArray && resolved.Type != spec.TypeObject && resolved.Type != "")
+	}
+	return true
+}
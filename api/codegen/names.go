@@ -0,0 +1,103 @@
+package codegen
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+//initialisms are written in upper case as per the Go naming conventions
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "TLS": true, "TTL": true, "UI": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+//reserved are the identifiers used by the generated code that cannot be used for the parameters
+var reserved = map[string]bool{
+	"w": true, "r": true, "router": true, "s": true, "params": true, "body": true, "err": true, "query": true,
+	"v": true, "raw": true, "values": true, "ok": true, "ctx": true, "c": true, "req": true, "resp": true,
+	"u": true, "q": true, "data": true, "result": true, "i": true, "fmt": true, "url": true, "http": true,
+	"json": true, "strings": true, "bytes": true, "context": true, "ioutil": true, "strconv": true, "io": true,
+	"payload": true, "reader": true,
+}
+
+//pathParamName is the name of the variable holding the path parameter, the reserved identifiers are suffixed with Param
+func pathParamName(name string) string {
+	goName := unexportedName(name)
+	if reserved[goName] {
+		goName += "Param"
+	}
+	return goName
+}
+
+//words splits the name at the non alphanumeric characters and at the case changes
+func words(name string) []string {
+	var result []string
+	runes := []rune(name)
+	start := -1
+	for i, c := range runes {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			if start >= 0 {
+				result = append(result, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		prev := runes[i-1]
+		//fooBar, foo1Bar and HTTPServer
+		if unicode.IsUpper(c) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			result = append(result, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		result = append(result, string(runes[start:]))
+	}
+	return result
+}
+
+//exportedName converts the name to an exported Go identifier
+func exportedName(name string) string {
+	var sb strings.Builder
+	for _, word := range words(name) {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			sb.WriteString(upper)
+		} else {
+			runes := []rune(strings.ToLower(word))
+			runes[0] = unicode.ToUpper(runes[0])
+			sb.WriteString(string(runes))
+		}
+	}
+	result := sb.String()
+	if result == "" {
+		return "Value"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		return "N" + result
+	}
+	return result
+}
+
+//unexportedName converts the name to an unexported Go identifier
+func unexportedName(name string) string {
+	parts := words(name)
+	if len(parts) == 0 {
+		return "value"
+	}
+	first := strings.ToLower(parts[0])
+	result := first + strings.TrimPrefix(exportedName(name), exportedName(parts[0]))
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "n" + result
+	}
+	if token.IsKeyword(result) {
+		result += "Value"
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//methodConstants are the turbo constants of the HTTP methods
+var methodConstants = map[string]string{
+	"GET": "GET", "HEAD": "HEAD", "POST": "POST", "PUT": "PUT", "DELETE": "DELETE", "OPTIONS": "OPTIONS",
+	"TRACE": "TRACE", "PATCH": "PATCH",
+}
+
+//server declares the Server interface and the Register function
+func (g *generator) server(ops []*operation) {
+	g.use("net/http")
+	g.use(TurboImport)
+	g.comment("Server is implemented by the handlers of the operations.\n" +
+		"The path parameters, the query, header and cookie parameters and the JSON request body are decoded before the handler is invoked.")
+	g.line("type Server interface {")
+	for _, o := range ops {
+		g.comment(fmt.Sprintf("%s handles %s %s", o.name, o.method, o.path))
+		if o.op.Summary != "" {
+			g.comment(o.op.Summary)
+		}
+		g.line("%s(%s)", o.name, strings.Join(g.handlerArgs(o), ", "))
+	}
+	g.line("}")
+	g.line("")
+	g.comment("Register binds the operations of the Server on the router.\n" +
+		"Requests with missing or malformed parameters or request body are rejected with 400 Bad Request.")
+	g.line("func Register(router *turbo.Router, s Server) {")
+	for _, o := range ops {
+		g.handler(o)
+	}
+	g.line("}")
+	g.line("")
+}
+
+//paramsStruct declares the struct of the query, header and cookie parameters of the operation
+func (g *generator) paramsStruct(o *operation) {
+	if len(o.params) == 0 {
+		return
+	}
+	g.comment(fmt.Sprintf("%sParams are the parameters of %s", o.name, o.name))
+	g.line("type %sParams struct {", o.name)
+	for _, p := range o.params {
+		if p.spec.Description != "" {
+			g.comment(p.spec.Description)
+		}
+		g.line("%s %s", p.goName, p.fieldType())
+	}
+	g.line("}")
+	g.line("")
+}
+
+//fieldType is the Go type of the parameter field, the optional scalars are pointers
+func (p *param) fieldType() string {
+	if p.array {
+		return "[]" + p.typ
+	}
+	if !p.spec.Required {
+		return "*" + p.typ
+	}
+	return p.typ
+}
+
+//handlerArgs are the arguments of the handler method of the operation
+func (g *generator) handlerArgs(o *operation) []string {
+	args := []string{"w http.ResponseWriter", "r *http.Request"}
+	for _, p := range o.pathParams {
+		args = append(args, p.goName+" "+p.typ)
+	}
+	if len(o.params) > 0 {
+		args = append(args, "params "+o.name+"Params")
+	}
+	if o.body != nil {
+		if o.body.required {
+			args = append(args, "body "+o.body.typ)
+		} else {
+			args = append(args, "body *"+o.body.typ)
+		}
+	}
+	return args
+}
+
+//handler registers the handler decoding the arguments of the operation
+func (g *generator) handler(o *operation) {
+	g.line("router.Add(%s, func(w http.ResponseWriter, r *http.Request) {", strconv.Quote(o.path))
+	call := []string{"w", "r"}
+	for _, p := range o.pathParams {
+		g.line("%s, err := router.%s(%s, r)", p.goName, pathGetter[p.typ], strconv.Quote(p.spec.Name))
+		g.badRequest("err != nil", "invalid path parameter "+p.spec.Name)
+		call = append(call, p.goName)
+	}
+	if len(o.params) > 0 {
+		g.line("var params %sParams", o.name)
+		for _, p := range o.params {
+			g.decodeParam(p)
+		}
+		call = append(call, "params")
+	}
+	if o.body != nil {
+		g.use("encoding/json")
+		if o.body.required {
+			g.line("var body %s", o.body.typ)
+			g.line("if err := json.NewDecoder(r.Body).Decode(&body); err != nil {")
+		} else {
+			g.use("io")
+			g.line("var body *%s", o.body.typ)
+			g.line("if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {")
+		}
+		g.line("http.Error(w, \"invalid request body: \"+err.Error(), http.StatusBadRequest)")
+		g.line("return")
+		g.line("}")
+		call = append(call, "body")
+	}
+	g.line("s.%s(%s)", o.name, strings.Join(call, ", "))
+	g.line("}, turbo.%s)", methodConstants[o.method])
+}
+
+//pathGetter are the router functions fetching the path parameters of the type
+var pathGetter = map[string]string{
+	"string":  "GetPathParams",
+	"int":     "GetIntPathParams",
+	"float64": "GetFloatPathParams",
+	"bool":    "GetBoolPathParams",
+}
+
+//badRequest rejects the request with the message when the condition holds
+func (g *generator) badRequest(condition, message string) {
+	g.line("if %s {", condition)
+	g.line("http.Error(w, %s, http.StatusBadRequest)", strconv.Quote(message))
+	g.line("return")
+	g.line("}")
+}
+
+//decodeParam decodes the query, header or cookie parameter into its field of the params struct
+func (g *generator) decodeParam(p *param) {
+	name := strconv.Quote(p.spec.Name)
+	field := "params." + p.goName
+	switch p.spec.In {
+	case "query":
+		g.line("if values, ok := r.URL.Query()[%s]; ok {", name)
+	case "header":
+		g.line("if values, ok := r.Header[http.CanonicalHeaderKey(%s)]; ok {", name)
+	case "cookie":
+		g.line("if c, err := r.Cookie(%s); err == nil {", name)
+		g.line("values := []string{c.Value}")
+	default:
+		return
+	}
+	if p.array {
+		if !p.spec.Exploded() || p.spec.In != "query" {
+			g.use("strings")
+			g.line("if len(values) == 1 {")
+			g.line("values = strings.Split(values[0], %s)", strconv.Quote(p.spec.Delimiter()))
+			g.line("}")
+		}
+		g.line("for _, raw := range values {")
+		g.line("%s = append(%s, %s)", field, field, g.parse(p))
+		g.line("}")
+	} else {
+		g.line("raw := values[0]")
+		v := g.parse(p)
+		if p.spec.Required {
+			g.line("%s = %s", field, v)
+		} else {
+			g.line("%s = &%s", field, v)
+		}
+	}
+	if p.spec.Required {
+		g.line("} else {")
+		g.line("http.Error(w, %s, http.StatusBadRequest)", strconv.Quote("missing "+p.spec.In+" parameter "+p.spec.Name))
+		g.line("return")
+	}
+	g.line("}")
+}
+
+//parse converts the raw value to the type of the parameter and returns the variable holding the value
+func (g *generator) parse(p *param) string {
+	switch p.typ {
+	case "int":
+		g.use("strconv")
+		g.line("v, err := strconv.Atoi(raw)")
+	case "float64":
+		g.use("strconv")
+		g.line("v, err := strconv.ParseFloat(raw, 64)")
+	case "bool":
+		g.use("strconv")
+		g.line("v, err := strconv.ParseBool(raw)")
+	default:
+		return "raw"
+	}
+	g.badRequest("err != nil", "invalid "+p.spec.In+" parameter "+p.spec.Name)
+	return "v"
+}
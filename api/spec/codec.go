@@ -0,0 +1,133 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//extensionPrefix is the prefix of the specification extension fields
+const extensionPrefix = "x-"
+
+//ToJSON serializes the OAS document to indented JSON
+func (oas *OAS) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(oas, "", "  ")
+}
+
+//ToYAML serializes the OAS document to YAML.
+//The json tags are the source of truth for the field names, the JSON document is converted to its YAML form
+//so that the embedded types are inlined the same way in both the formats.
+func (oas *OAS) ToYAML() ([]byte, error) {
+	data, err := json.Marshal(oas)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+//jsonToYAML converts a JSON document to a block styled YAML document retaining the order of the keys.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//blockStyle resets the flow/quoted styles that the nodes inherit from the JSON syntax
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+//MarshalJSON inlines the Extension fields of the schema
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	data, err := json.Marshal(schema(s))
+	if err != nil || len(s.Extension) == 0 {
+		return data, err
+	}
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range s.Extension {
+		fields[k] = v
+	}
+	return json.Marshal(fields)
+}
+
+//UnmarshalJSON collects the x- prefixed fields of the schema into the Extension
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	var v schema
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for k, raw := range fields {
+		if !strings.HasPrefix(k, extensionPrefix) {
+			continue
+		}
+		if v.Extension == nil {
+			v.Extension = make(Extension)
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		v.Extension[k] = value
+	}
+	*s = Schema(v)
+	return nil
+}
+
+//MarshalJSON serializes the security requirement as the map of the scheme names to the scopes
+func (s SecurityRequirement) MarshalJSON() ([]byte, error) {
+	if s.Fields == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(s.Fields)
+}
+
+//UnmarshalJSON reads the map of the scheme names to the scopes into Fields
+func (s *SecurityRequirement) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.Fields)
+}
+
+//MarshalJSON serializes the callback as the reference or the map of the expressions to the path items
+func (c Callback) MarshalJSON() ([]byte, error) {
+	if c.Ref != nil {
+		return json.Marshal(c.Reference)
+	}
+	return json.Marshal(c.Callbacks)
+}
+
+//UnmarshalJSON reads either the reference or the map of the expressions to the path items
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if _, ok := fields["$ref"]; ok {
+		return json.Unmarshal(data, &c.Reference)
+	}
+	return json.Unmarshal(data, &c.Callbacks)
+}
@@ -0,0 +1,85 @@
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testOAS() *OAS {
+	ref := "#/components/schemas/User"
+	return &OAS{
+		OpenAPI: Version,
+		Info:    Info{Title: "Users", Version: "1.0.0"},
+		Paths: map[string]*PathItem{
+			"/users/{id}": {
+				Get: &Operation{
+					OperationID: "getUser",
+					Parameters: []*Parameter{
+						{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+					},
+					Responses: map[string]*Response{
+						"200": {
+							Description: "the user",
+							Content: map[string]MediaType{
+								"application/json": {Schema: &Schema{Reference: Reference{Ref: &ref}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"User": {
+					Type:      "object",
+					Required:  []string{"id"},
+					Extension: Extension{"x-go-name": "User"},
+					Properties: map[string]*Schema{
+						"id":      {Type: "string"},
+						"version": {Type: "string", Enum: []interface{}{"true", "1.0"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestOAS_ToJSON(t *testing.T) {
+	data, err := testOAS().ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{`"openapi": "3.1.0"`, `"$ref": "#/components/schemas/User"`, `"x-go-name": "User"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToJSON() missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Extension") || strings.Contains(got, "discriminator") {
+		t.Errorf("ToJSON() has empty fields %s", got)
+	}
+	var oas OAS
+	if err = json.Unmarshal(data, &oas); err != nil {
+		t.Fatal(err)
+	}
+	if oas.Components.Schemas["User"].Extension["x-go-name"] != "User" {
+		t.Errorf("UnmarshalJSON() extension = %v", oas.Components.Schemas["User"].Extension)
+	}
+}
+
+func TestOAS_ToYAML(t *testing.T) {
+	data, err := testOAS().ToYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"openapi: 3.1.0\n", "    get:\n", "$ref: '#/components/schemas/User'", `- "true"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToYAML() missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(got, ": {") || strings.Contains(got, ": [") {
+		t.Errorf("ToYAML() has flow style %s", got)
+	}
+}
@@ -0,0 +1,615 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Change is a difference between two versions of an OAS document
+type Change struct {
+	//Pointer locates the change in the new document, or in the old document if the element was removed
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+	//Breaking is set if the clients of the old document may fail against the new document
+	Breaking bool `json:"breaking"`
+}
+
+func (c *Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("[%s] %s: %s", kind, c.Pointer, c.Message)
+}
+
+//DiffReport lists the changes between two versions of an OAS document
+type DiffReport struct {
+	Changes []*Change `json:"changes"`
+}
+
+//HasBreaking checks if any of the changes is breaking
+func (r *DiffReport) HasBreaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+//Breaking returns the breaking changes
+func (r *DiffReport) Breaking() []*Change {
+	var changes []*Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+//Text formats the report with a change per line, the breaking changes are listed first
+func (r *DiffReport) Text() string {
+	var sb strings.Builder
+	breaking := r.Breaking()
+	fmt.Fprintf(&sb, "%d change(s), %d breaking\n", len(r.Changes), len(breaking))
+	for _, c := range breaking {
+		sb.WriteString(c.String())
+		sb.WriteByte('\n')
+	}
+	for _, c := range r.Changes {
+		if !c.Breaking {
+			sb.WriteString(c.String())
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+//JSON formats the report as a JSON document
+func (r *DiffReport) JSON() ([]byte, error) {
+	if r.Changes == nil {
+		return json.MarshalIndent(&DiffReport{Changes: []*Change{}}, "", "  ")
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
+
+//direction of the data described by a schema, the compatibility rules of the requests and the responses are opposite
+type direction int
+
+const (
+	//request schemas are written by the clients
+	request direction = iota
+	//response schemas are read by the clients
+	response
+)
+
+//differ collects the changes between the old and the new document
+type differ struct {
+	old, new *OAS
+	changes  []*Change
+	//visited holds the pairs of schema references being compared to stop at the recursive schemas
+	visited map[string]bool
+}
+
+//Diff compares the old and the new versions of an OAS document and classifies every change of the paths, the
+//operations, the parameters, the request bodies and the responses as breaking or non-breaking for the clients of the
+//old version. The schemas are compared through their references, the members of allOf are merged into the schema and
+//the members of oneOf and anyOf are compared in their order.
+func Diff(old, new *OAS) *DiffReport {
+	d := &differ{old: old, new: new, visited: make(map[string]bool)}
+	for _, path := range mergedKeys(old.Paths, new.Paths) {
+		pointer := "/paths/" + escapePointer(path)
+		oldItem, newItem := old.Paths[path], new.Paths[path]
+		switch {
+		case newItem == nil:
+			d.add(pointer, true, "path removed")
+		case oldItem == nil:
+			d.add(pointer, false, "path added")
+		default:
+			d.pathItem(pointer, oldItem, newItem)
+		}
+	}
+	d.security("/security", old.Security, new.Security)
+	return &DiffReport{Changes: d.changes}
+}
+
+func (d *differ) add(pointer string, breaking bool, message string) {
+	d.changes = append(d.changes, &Change{Pointer: pointer, Message: message, Breaking: breaking})
+}
+
+func (d *differ) pathItem(pointer string, oldItem, newItem *PathItem) {
+	oldOps, newOps := oldItem.Operations(), newItem.Operations()
+	for _, method := range mergedKeys(oldOps, newOps) {
+		opPointer := pointer + "/" + strings.ToLower(method)
+		oldOp, newOp := oldOps[method], newOps[method]
+		switch {
+		case newOp == nil:
+			d.add(opPointer, true, "operation "+method+" removed")
+		case oldOp == nil:
+			d.add(opPointer, false, "operation "+method+" added")
+		default:
+			d.operation(opPointer, oldItem, newItem, oldOp, newOp)
+		}
+	}
+}
+
+func (d *differ) operation(pointer string, oldItem, newItem *PathItem, oldOp, newOp *Operation) {
+	if newOp.Deprecated && !oldOp.Deprecated {
+		d.add(pointer+"/deprecated", false, "operation deprecated")
+	}
+	oldParams := d.old.parameters(pointer, oldItem, oldOp)
+	newParams := d.new.parameters(pointer, newItem, newOp)
+	for _, key := range mergedKeys(oldParams, newParams) {
+		o, n := oldParams[key], newParams[key]
+		switch {
+		case n == nil:
+			d.add(o.pointer, false, key+" parameter removed")
+		case o == nil:
+			d.add(n.pointer, n.Required, key+" parameter added"+requiredSuffix(n.Required))
+		default:
+			if n.Required && !o.Required {
+				d.add(n.pointer+"/required", true, key+" parameter became required")
+			} else if !n.Required && o.Required {
+				d.add(n.pointer+"/required", false, key+" parameter became optional")
+			}
+			d.schema(n.pointer+"/schema", o.Schema, n.Schema, request)
+		}
+	}
+	d.requestBody(pointer+"/requestBody", oldOp.RequestBody, newOp.RequestBody)
+	for _, code := range mergedKeys(oldOp.Responses, newOp.Responses) {
+		rp := pointer + "/responses/" + escapePointer(code)
+		o, n := d.old.response(oldOp.Responses[code]), d.new.response(newOp.Responses[code])
+		switch {
+		case n == nil && o != nil:
+			d.add(rp, true, "response "+code+" removed")
+		case o == nil && n != nil:
+			d.add(rp, false, "response "+code+" added")
+		case o != nil && n != nil:
+			d.content(rp+"/content", o.Content, n.Content, response)
+			for _, name := range mergedKeys(o.Headers, n.Headers) {
+				if _, ok := n.Headers[name]; !ok {
+					d.add(rp+"/headers/"+escapePointer(name), true, "response header "+name+" removed")
+				}
+			}
+		}
+	}
+	//the operations inheriting the security of the document both times are covered by the comparison of the document
+	if oldOp.Security != nil || newOp.Security != nil {
+		d.security(pointer+"/security", d.old.security(oldOp), d.new.security(newOp))
+	}
+}
+
+//security returns the security requirements of the operation, the ones of the document if it does not declare any.
+//An empty list declared by the operation accepts the anonymous requests.
+func (oas *OAS) security(op *Operation) []*SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return oas.Security
+}
+
+//locatedParameter is a parameter along with its pointer in the document
+type locatedParameter struct {
+	*Parameter
+	pointer string
+}
+
+//parameters returns the parameters of the operation keyed by <in> <name>, the operation parameters override the
+//parameters of the path item
+func (oas *OAS) parameters(pointer string, item *PathItem, op *Operation) map[string]*locatedParameter {
+	params := make(map[string]*locatedParameter)
+	itemPointer := pointer[:strings.LastIndex(pointer, "/")]
+	for i := range item.Parameters {
+		if p := oas.parameter(&item.Parameters[i]); p != nil {
+			params[p.In+" "+p.Name] = &locatedParameter{p, fmt.Sprintf("%s/parameters/%d", itemPointer, i)}
+		}
+	}
+	for i, p := range op.Parameters {
+		if p = oas.parameter(p); p != nil {
+			params[p.In+" "+p.Name] = &locatedParameter{p, fmt.Sprintf("%s/parameters/%d", pointer, i)}
+		}
+	}
+	return params
+}
+
+//parameter resolves the local parameter reference
+func (oas *OAS) parameter(p *Parameter) *Parameter {
+	if p == nil || p.Ref == nil {
+		return p
+	}
+	name := strings.TrimPrefix(*p.Ref, componentPrefix+"parameters/")
+	if oas.Components == nil {
+		return nil
+	}
+	return oas.Components.Parameters[unescapePointer(name)]
+}
+
+//requestBody resolves the local request body reference
+func (oas *OAS) requestBody(b *RequestBody) *RequestBody {
+	if b == nil || b.Ref == nil {
+		return b
+	}
+	name := strings.TrimPrefix(*b.Ref, componentPrefix+"requestBodies/")
+	if oas.Components == nil {
+		return nil
+	}
+	return oas.Components.RequestBodies[unescapePointer(name)]
+}
+
+//response resolves the local response reference
+func (oas *OAS) response(r *Response) *Response {
+	if r == nil || r.Ref == nil {
+		return r
+	}
+	name := strings.TrimPrefix(*r.Ref, componentPrefix+"responses/")
+	if oas.Components == nil {
+		return nil
+	}
+	return oas.Components.Responses[unescapePointer(name)]
+}
+
+func (d *differ) requestBody(pointer string, oldBody, newBody *RequestBody) {
+	o, n := d.old.requestBody(oldBody), d.new.requestBody(newBody)
+	switch {
+	case o == nil && n == nil:
+	case n == nil:
+		d.add(pointer, false, "request body removed")
+	case o == nil:
+		d.add(pointer, n.Required, "request body added"+requiredSuffix(n.Required))
+	default:
+		if n.Required && !o.Required {
+			d.add(pointer+"/required", true, "request body became required")
+		}
+		d.content(pointer+"/content", o.Content, n.Content, request)
+	}
+}
+
+//content compares the media types, a media type removed from a request or a response is breaking
+func (d *differ) content(pointer string, oldContent, newContent map[string]MediaType, dir direction) {
+	for _, mediaType := range mergedKeys(oldContent, newContent) {
+		mp := pointer + "/" + escapePointer(mediaType)
+		o, inOld := oldContent[mediaType]
+		n, inNew := newContent[mediaType]
+		switch {
+		case !inNew:
+			d.add(mp, true, "media type "+mediaType+" removed")
+		case !inOld:
+			d.add(mp, false, "media type "+mediaType+" added")
+		default:
+			d.schema(mp+"/schema", o.Schema, n.Schema, dir)
+		}
+	}
+}
+
+//schema compares the schemas as per the direction of the data, the values accepted by a request schema must not
+//shrink and the values produced by a response schema must not grow.
+func (d *differ) schema(pointer string, oldSchema, newSchema *Schema, dir direction) {
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+	if oldSchema.Ref != nil && newSchema.Ref != nil {
+		key := fmt.Sprintf("%s|%s|%d", *oldSchema.Ref, *newSchema.Ref, dir)
+		if d.visited[key] {
+			return
+		}
+		d.visited[key] = true
+		defer delete(d.visited, key)
+	}
+	o, err := d.old.ResolveSchema(oldSchema)
+	if err != nil || o == nil {
+		return
+	}
+	n, err := d.new.ResolveSchema(newSchema)
+	if err != nil || n == nil {
+		return
+	}
+	//the members of allOf are merged so that the properties they declare are compared as the ones of the schema
+	o, n = d.old.allOf(o, nil), d.new.allOf(n, nil)
+	if o.Type != n.Type {
+		d.add(pointer+"/type", true, fmt.Sprintf("type changed from %s to %s", typeName(o.Type), typeName(n.Type)))
+		return
+	}
+	if format, oldFormat := stringOf(n.Format), stringOf(o.Format); format != oldFormat {
+		d.add(pointer+"/format", true, fmt.Sprintf("format changed from %s to %s", typeName(oldFormat), typeName(format)))
+	}
+	if n.Nullable != o.Nullable {
+		//a request schema accepting null or a response schema not producing null is compatible
+		d.add(pointer+"/nullable", (n.Nullable && dir == response) || (!n.Nullable && dir == request),
+			fmt.Sprintf("nullable changed to %v", n.Nullable))
+	}
+	d.enum(pointer+"/enum", o.Enum, n.Enum, dir)
+	d.constraints(pointer, o, n, dir)
+	d.schema(pointer+"/items", o.Items, n.Items, dir)
+	d.properties(pointer, o, n, dir)
+	d.alternatives(pointer+"/oneOf", o.OneOf, n.OneOf, dir)
+	d.alternatives(pointer+"/anyOf", o.AnyOf, n.AnyOf, dir)
+}
+
+//allOf returns a copy of the schema with the properties and the required properties of the members of its allOf
+//added, the type of the first typed member is used if the schema has none. The seen schemas are not merged again.
+func (oas *OAS) allOf(s *Schema, seen map[*Schema]bool) *Schema {
+	if len(s.AllOf) == 0 || seen[s] {
+		return s
+	}
+	if seen == nil {
+		seen = make(map[*Schema]bool)
+	}
+	seen[s] = true
+	defer delete(seen, s)
+	merged := *s
+	merged.AllOf = nil
+	merged.Properties = make(map[string]*Schema, len(s.Properties))
+	for name, property := range s.Properties {
+		merged.Properties[name] = property
+	}
+	merged.Required = append([]string(nil), s.Required...)
+	for _, member := range s.AllOf {
+		m, err := oas.ResolveSchema(member)
+		if err != nil || m == nil {
+			continue
+		}
+		m = oas.allOf(m, seen)
+		if merged.Type == "" {
+			merged.Type = m.Type
+		}
+		for name, property := range m.Properties {
+			if _, ok := merged.Properties[name]; !ok {
+				merged.Properties[name] = property
+			}
+		}
+		merged.Required = append(merged.Required, m.Required...)
+	}
+	return &merged
+}
+
+//alternatives compares the members of oneOf or anyOf in their order, an alternative added to a response or removed
+//from a request is breaking
+func (d *differ) alternatives(pointer string, o, n []*Schema, dir direction) {
+	for i := 0; i < len(o) && i < len(n); i++ {
+		d.schema(fmt.Sprintf("%s/%d", pointer, i), o[i], n[i], dir)
+	}
+	for i := len(n); i < len(o); i++ {
+		d.add(fmt.Sprintf("%s/%d", pointer, i), dir == request, "alternative removed")
+	}
+	for i := len(o); i < len(n); i++ {
+		d.add(fmt.Sprintf("%s/%d", pointer, i), dir == response, "alternative added")
+	}
+}
+
+//enum compares the enumerated values, a narrowed request enum or a widened response enum is breaking
+func (d *differ) enum(pointer string, oldEnum, newEnum []interface{}, dir direction) {
+	if len(oldEnum) == 0 && len(newEnum) == 0 {
+		return
+	}
+	var removed, added []string
+	for _, v := range oldEnum {
+		if len(newEnum) > 0 && !inEnum(v, newEnum) {
+			removed = append(removed, fmt.Sprint(v))
+		}
+	}
+	for _, v := range newEnum {
+		if len(oldEnum) == 0 || !inEnum(v, oldEnum) {
+			added = append(added, fmt.Sprint(v))
+		}
+	}
+	if len(removed) > 0 {
+		d.add(pointer, dir == request, "enum values removed: "+strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(pointer, dir == response, "enum values added: "+strings.Join(added, ", "))
+	}
+}
+
+//constraints compares the bounds, a tightened request bound or a loosened response bound is breaking
+func (d *differ) constraints(pointer string, o, n *Schema, dir direction) {
+	compare := func(name string, oldBound, newBound *float64, upper bool) {
+		if oldBound == nil && newBound == nil || oldBound != nil && newBound != nil && *oldBound == *newBound {
+			return
+		}
+		//tightened when a bound is added, an upper bound decreases or a lower bound increases
+		tightened := oldBound == nil ||
+			(newBound != nil && ((upper && *newBound < *oldBound) || (!upper && *newBound > *oldBound)))
+		message := name + " "
+		switch {
+		case newBound == nil:
+			message += "removed"
+		case oldBound == nil:
+			message += fmt.Sprintf("set to %v", *newBound)
+		default:
+			message += fmt.Sprintf("changed from %v to %v", *oldBound, *newBound)
+		}
+		d.add(pointer+"/"+name, tightened == (dir == request), message)
+	}
+	compare("maximum", o.Maximum, n.Maximum, true)
+	compare("minimum", o.Minimum, n.Minimum, false)
+	compare("maxLength", intBound(o.MaxLength), intBound(n.MaxLength), true)
+	compare("minLength", intBound(o.MinLength), intBound(n.MinLength), false)
+	compare("maxItems", intBound(o.MaxItems), intBound(n.MaxItems), true)
+	compare("minItems", intBound(o.MinItems), intBound(n.MinItems), false)
+	if stringOf(o.Pattern) != stringOf(n.Pattern) {
+		d.add(pointer+"/pattern", n.Pattern != nil && dir == request || o.Pattern != nil && dir == response,
+			fmt.Sprintf("pattern changed from %s to %s", typeName(stringOf(o.Pattern)), typeName(stringOf(n.Pattern))))
+	}
+}
+
+//properties compares the object properties.
+//For the requests a new required property is breaking, for the responses a removed or no longer required property
+//is breaking.
+func (d *differ) properties(pointer string, o, n *Schema, dir direction) {
+	oldRequired, newRequired := stringSet(o.Required), stringSet(n.Required)
+	for _, name := range mergedKeys(o.Properties, n.Properties) {
+		pp := pointer + "/properties/" + escapePointer(name)
+		oldProperty, inOld := o.Properties[name]
+		newProperty, inNew := n.Properties[name]
+		switch {
+		case !inNew:
+			d.add(pp, dir == response, "property "+name+" removed")
+		case !inOld:
+			d.add(pp, dir == request && newRequired[name], "property "+name+" added"+requiredSuffix(newRequired[name]))
+		default:
+			d.schema(pp, oldProperty, newProperty, dir)
+		}
+	}
+	for _, name := range n.Required {
+		if _, existing := o.Properties[name]; !oldRequired[name] && existing {
+			d.add(pointer+"/required", dir == request, "property "+name+" became required")
+		}
+	}
+	for _, name := range o.Required {
+		if _, ok := n.Properties[name]; !newRequired[name] && ok {
+			d.add(pointer+"/required", dir == response, "property "+name+" became optional")
+		}
+	}
+}
+
+//security compares the security requirements, a list of alternatives one of which has to be satisfied. Requiring
+//security where none was or no longer accepting an alternative is breaking while adding an alternative is not.
+func (d *differ) security(pointer string, oldSecurity, newSecurity []*SecurityRequirement) {
+	oldNames, newNames := securityNames(oldSecurity), securityNames(newSecurity)
+	//no requirement or an empty one accepts the anonymous requests
+	if len(newNames) == 0 || newNames[""] {
+		if len(oldNames) > 0 && !oldNames[""] {
+			d.add(pointer, false, "anonymous requests accepted")
+		}
+		return
+	}
+	if len(oldNames) == 0 {
+		d.add(pointer, true, "security requirement added: "+strings.Join(sortedKeys(newNames), ", "))
+		return
+	}
+	var removed, added []string
+	for _, name := range sortedKeys(oldNames) {
+		if !newNames[name] && name == "" {
+			removed = append(removed, "anonymous")
+		} else if !newNames[name] {
+			removed = append(removed, name)
+		}
+	}
+	for _, name := range sortedKeys(newNames) {
+		if !oldNames[name] {
+			added = append(added, name)
+		}
+	}
+	if len(removed) > 0 {
+		d.add(pointer, true, "security requirement removed: "+strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(pointer, false, "security requirement added: "+strings.Join(added, ", "))
+	}
+}
+
+//securityNames returns the alternatives of the security requirements, the schemes of an alternative are joined by +
+func securityNames(requirements []*SecurityRequirement) map[string]bool {
+	set := make(map[string]bool)
+	for _, r := range requirements {
+		if r == nil {
+			continue
+		}
+		keys := make([]string, 0, len(r.Fields))
+		for k := range r.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		set[strings.Join(keys, "+")] = true
+	}
+	return set
+}
+
+//mergedKeys returns the sorted union of the keys of the maps, both of the same type
+func mergedKeys(a, b interface{}) []string {
+	set := make(map[string]bool)
+	switch a := a.(type) {
+	case map[string]*PathItem:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]*PathItem) {
+			set[k] = true
+		}
+	case map[string]*Operation:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]*Operation) {
+			set[k] = true
+		}
+	case map[string]*locatedParameter:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]*locatedParameter) {
+			set[k] = true
+		}
+	case map[string]*Response:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]*Response) {
+			set[k] = true
+		}
+	case map[string]Header:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]Header) {
+			set[k] = true
+		}
+	case map[string]MediaType:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]MediaType) {
+			set[k] = true
+		}
+	case map[string]*Schema:
+		for k := range a {
+			set[k] = true
+		}
+		for k := range b.(map[string]*Schema) {
+			set[k] = true
+		}
+	default:
+		panic(fmt.Sprintf("mergedKeys of unsupported map type %T", a))
+	}
+	return sortedKeys(set)
+}
+
+func requiredSuffix(required bool) string {
+	if required {
+		return " as required"
+	}
+	return ""
+}
+
+func typeName(t string) string {
+	if t == "" {
+		return "none"
+	}
+	return t
+}
+
+func stringOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intBound(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	f := float64(*i)
+	return &f
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,421 @@
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const diffBase = `openapi: 3.1.0
+info: {title: Petstore, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses: {'201': {description: created}}
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: string}}
+      responses: {'200': {description: ok}}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string}
+        status: {type: string, enum: [available, sold]}
+        parent: {$ref: '#/components/schemas/Pet'}
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		pointer  string
+		breaking bool
+	}{
+		{
+			name:     "path removed",
+			old:      "  /pets/{petId}:",
+			new:      "  /pets/{id}:",
+			pointer:  "/paths/~1pets~1{petId}",
+			breaking: true,
+		},
+		{
+			name:     "path added",
+			old:      "  /pets/{petId}:",
+			new:      "  /pets/{id}:",
+			pointer:  "/paths/~1pets~1{id}",
+			breaking: false,
+		},
+		{
+			name:     "operation removed",
+			old:      "    post:",
+			new:      "    put:",
+			pointer:  "/paths/~1pets/post",
+			breaking: true,
+		},
+		{
+			name:     "required parameter added",
+			old:      "        - {name: limit, in: query, schema: {type: integer, maximum: 100}}",
+			new:      "        - {name: limit, in: query, schema: {type: integer, maximum: 100}}\n        - {name: page, in: query, required: true, schema: {type: integer}}",
+			pointer:  "/paths/~1pets/get/parameters/1",
+			breaking: true,
+		},
+		{
+			name:     "optional parameter added",
+			old:      "        - {name: limit, in: query, schema: {type: integer, maximum: 100}}",
+			new:      "        - {name: limit, in: query, schema: {type: integer, maximum: 100}}\n        - {name: page, in: query, schema: {type: integer}}",
+			pointer:  "/paths/~1pets/get/parameters/1",
+			breaking: false,
+		},
+		{
+			name:     "parameter became required",
+			old:      "{name: limit, in: query, schema",
+			new:      "{name: limit, in: query, required: true, schema",
+			pointer:  "/paths/~1pets/get/parameters/0/required",
+			breaking: true,
+		},
+		{
+			name:     "parameter type changed",
+			old:      "{name: petId, in: path, required: true, schema: {type: string}}",
+			new:      "{name: petId, in: path, required: true, schema: {type: integer}}",
+			pointer:  "/paths/~1pets~1{petId}/get/parameters/0/schema/type",
+			breaking: true,
+		},
+		{
+			name:     "request maximum tightened",
+			old:      "maximum: 100",
+			new:      "maximum: 50",
+			pointer:  "/paths/~1pets/get/parameters/0/schema/maximum",
+			breaking: true,
+		},
+		{
+			name:     "request maximum loosened",
+			old:      "maximum: 100",
+			new:      "maximum: 500",
+			pointer:  "/paths/~1pets/get/parameters/0/schema/maximum",
+			breaking: false,
+		},
+		{
+			name:     "enum narrowed in the request",
+			old:      "enum: [available, sold]",
+			new:      "enum: [available]",
+			pointer:  "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/status/enum",
+			breaking: true,
+		},
+		{
+			name:     "enum widened in the response",
+			old:      "enum: [available, sold]",
+			new:      "enum: [available, sold, pending]",
+			pointer:  "/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/status/enum",
+			breaking: true,
+		},
+		{
+			name:     "enum widened in the request",
+			old:      "enum: [available, sold]",
+			new:      "enum: [available, sold, pending]",
+			pointer:  "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/status/enum",
+			breaking: false,
+		},
+		{
+			name:     "response field removed",
+			old:      "        tag: {type: string}\n",
+			new:      "",
+			pointer:  "/paths/~1pets/get/responses/200/content/application~1json/schema/items/properties/tag",
+			breaking: true,
+		},
+		{
+			name:     "request field removed",
+			old:      "        tag: {type: string}\n",
+			new:      "",
+			pointer:  "/paths/~1pets/post/requestBody/content/application~1json/schema/properties/tag",
+			breaking: false,
+		},
+		{
+			name:     "request body became required",
+			old:      "      requestBody:\n",
+			new:      "      requestBody:\n        required: true\n",
+			pointer:  "/paths/~1pets/post/requestBody/required",
+			breaking: true,
+		},
+		{
+			name:     "response removed",
+			old:      "responses: {'201': {description: created}}",
+			new:      "responses: {'202': {description: accepted}}",
+			pointer:  "/paths/~1pets/post/responses/201",
+			breaking: true,
+		},
+		{
+			name:     "media type removed",
+			old:      "          application/json:\n              schema: {type: array",
+			new:      "          application/xml:\n              schema: {type: array",
+			pointer:  "/paths/~1pets/get/responses/200/content/application~1json",
+			breaking: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := Parse([]byte(diffBase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(diffBase, tt.old) {
+				t.Fatalf("base document does not contain %q", tt.old)
+			}
+			new, err := Parse([]byte(strings.Replace(diffBase, tt.old, tt.new, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := Diff(old, new)
+			for _, c := range report.Changes {
+				if c.Pointer == tt.pointer {
+					if c.Breaking != tt.breaking {
+						t.Errorf("Diff() %s breaking = %v, want %v", c, c.Breaking, tt.breaking)
+					}
+					return
+				}
+			}
+			t.Errorf("Diff() = %v, want a change at %s", report.Text(), tt.pointer)
+		})
+	}
+}
+
+const diffComposed = `openapi: 3.1.0
+info: {title: Petstore, version: 1.0.0}
+paths:
+  /pets/{petId}:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: '#/components/schemas/NewPet'
+                  - {required: [id], properties: {id: {type: integer}}}
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              oneOf:
+                - $ref: '#/components/schemas/NewPet'
+                - {type: string}
+      responses: {'204': {description: updated}}
+components:
+  schemas:
+    NewPet:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+func TestDiff_Composed(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		pointer  string
+		breaking bool
+	}{
+		{
+			name:     "allOf response field removed",
+			old:      "                  - {required: [id], properties: {id: {type: integer}}}\n",
+			new:      "",
+			pointer:  "/paths/~1pets~1{petId}/get/responses/200/content/application~1json/schema/properties/id",
+			breaking: true,
+		},
+		{
+			name:     "allOf referenced response field removed",
+			old:      "        name: {type: string}",
+			new:      "        tag: {type: string}",
+			pointer:  "/paths/~1pets~1{petId}/get/responses/200/content/application~1json/schema/properties/name",
+			breaking: true,
+		},
+		{
+			name:     "oneOf request alternative removed",
+			old:      "                - {type: string}\n",
+			new:      "",
+			pointer:  "/paths/~1pets~1{petId}/put/requestBody/content/application~1json/schema/oneOf/1",
+			breaking: true,
+		},
+		{
+			name:     "oneOf request alternative changed",
+			old:      "                - {type: string}",
+			new:      "                - {type: integer}",
+			pointer:  "/paths/~1pets~1{petId}/put/requestBody/content/application~1json/schema/oneOf/1/type",
+			breaking: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := Parse([]byte(diffComposed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(diffComposed, tt.old) {
+				t.Fatalf("document does not contain %q", tt.old)
+			}
+			new, err := Parse([]byte(strings.Replace(diffComposed, tt.old, tt.new, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := Diff(old, new)
+			for _, c := range report.Changes {
+				if c.Pointer == tt.pointer {
+					if c.Breaking != tt.breaking {
+						t.Errorf("Diff() %s breaking = %v, want %v", c, c.Breaking, tt.breaking)
+					}
+					return
+				}
+			}
+			t.Errorf("Diff() = %v, want a change at %s", report.Text(), tt.pointer)
+		})
+	}
+}
+
+func TestDiff_Security(t *testing.T) {
+	const doc = "openapi: 3.1.0\ninfo: {title: Petstore, version: 1.0.0}\npaths: {}\n"
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		breaking []bool
+	}{
+		{name: "required where none was", old: "", new: "security: [{apiKey: []}]", breaking: []bool{true}},
+		{name: "alternative added", old: "security: [{apiKey: []}]", new: "security: [{apiKey: []}, {oauth: []}]", breaking: []bool{false}},
+		{name: "alternative removed", old: "security: [{apiKey: []}, {oauth: []}]", new: "security: [{oauth: []}]", breaking: []bool{true}},
+		{name: "alternative replaced", old: "security: [{apiKey: []}]", new: "security: [{oauth: []}]", breaking: []bool{true, false}},
+		{name: "scheme added to the alternative", old: "security: [{apiKey: []}]", new: "security: [{apiKey: [], oauth: []}]", breaking: []bool{true, false}},
+		{name: "anonymous no longer accepted", old: "security: [{}, {apiKey: []}]", new: "security: [{apiKey: []}]", breaking: []bool{true}},
+		{name: "anonymous accepted", old: "security: [{apiKey: []}]", new: "security: [{}, {apiKey: []}]", breaking: []bool{false}},
+		{name: "security removed", old: "security: [{apiKey: []}]", new: "", breaking: []bool{false}},
+		{name: "security unchanged", old: "security: [{apiKey: []}]", new: "security: [{apiKey: []}]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := Parse([]byte(doc + tt.old))
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := Parse([]byte(doc + tt.new))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := Diff(old, new)
+			if len(report.Changes) != len(tt.breaking) {
+				t.Fatalf("Diff() = %v, want %d change(s)", report.Text(), len(tt.breaking))
+			}
+			for i, c := range report.Changes {
+				if c.Pointer != "/security" || c.Breaking != tt.breaking[i] {
+					t.Errorf("Diff() %s breaking = %v, want %v at /security", c, c.Breaking, tt.breaking[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_OperationSecurity(t *testing.T) {
+	const doc = `openapi: 3.1.0
+info: {title: Petstore, version: 1.0.0}
+security: [{api_key: []}]
+paths:
+  /pets:
+    get:
+      responses: {'200': {description: ok}}
+`
+	const op = "    get:\n"
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		breaking []bool
+	}{
+		{name: "inherited requirement repeated", old: "", new: "      security: [{api_key: []}]\n"},
+		{name: "anonymous to inherited", old: "      security: []\n", new: "", breaking: []bool{true}},
+		{name: "inherited to anonymous", old: "", new: "      security: []\n", breaking: []bool{false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := Parse([]byte(strings.Replace(doc, op, op+tt.old, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := Parse([]byte(strings.Replace(doc, op, op+tt.new, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := Diff(old, new)
+			if len(report.Changes) != len(tt.breaking) {
+				t.Fatalf("Diff() = %v, want %d change(s)", report.Text(), len(tt.breaking))
+			}
+			for i, c := range report.Changes {
+				if c.Pointer != "/paths/~1pets/get/security" || c.Breaking != tt.breaking[i] {
+					t.Errorf("Diff() %s breaking = %v, want %v at /paths/~1pets/get/security", c, c.Breaking, tt.breaking[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_Unchanged(t *testing.T) {
+	old, err := Parse([]byte(diffBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := Parse([]byte(diffBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Diff(old, new)
+	if len(report.Changes) != 0 || report.HasBreaking() {
+		t.Errorf("Diff() = %v, want no changes", report.Text())
+	}
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"changes": []`) {
+		t.Errorf("JSON() = %s", data)
+	}
+}
+
+func TestDiffReport_Formats(t *testing.T) {
+	report := &DiffReport{Changes: []*Change{
+		{Pointer: "/paths/~1a", Message: "path added"},
+		{Pointer: "/paths/~1b", Message: "path removed", Breaking: true},
+	}}
+	want := "2 change(s), 1 breaking\n[breaking] /paths/~1b: path removed\n[non-breaking] /paths/~1a: path added\n"
+	if got := report.Text(); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DiffReport
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Changes) != 2 || !decoded.Changes[1].Breaking || decoded.Changes[1].Pointer != "/paths/~1b" {
+		t.Errorf("JSON() = %s", data)
+	}
+}
@@ -0,0 +1,437 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	//parameterLocations are the valid values of the in field of a parameter
+	parameterLocations = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+	//securitySchemeTypes are the valid values of the type field of a security scheme
+	securitySchemeTypes = map[string]bool{"apiKey": true, "http": true, "mutualTLS": true, "oauth2": true, "openIdConnect": true}
+	//apiKeyLocations are the valid values of the in field of an apiKey security scheme
+	apiKeyLocations = map[string]bool{"query": true, "header": true, "cookie": true}
+	//componentNameExp is the pattern the keys of the components must match
+	componentNameExp = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+	//pathVarExp matches the template expressions of a path
+	pathVarExp = regexp.MustCompile(`{([^{}]+)}`)
+)
+
+//linter collects the violations found in the document
+type linter struct {
+	oas  *OAS
+	errs []*ValidationError
+	//operationIds maps the operation ids to the pointer of their first occurrence
+	operationIds map[string]string
+}
+
+//Validate checks the structure of the document against the rules of the specification, the fields marked Required,
+//the enumerated values, the mutually exclusive fields, the uniqueness of the operationIds and the declaration of the
+//path template variables. Every violation is returned with the JSON pointer to its location in the document.
+func (oas *OAS) Validate() []*ValidationError {
+	l := &linter{oas: oas, operationIds: make(map[string]string)}
+	if oas.OpenAPI == "" {
+		l.fail("/openapi", "openapi is required")
+	} else if !strings.HasPrefix(oas.OpenAPI, "3.") {
+		l.fail("/openapi", fmt.Sprintf("unsupported openapi version %s", oas.OpenAPI))
+	}
+	l.info(&oas.Info)
+	for i, server := range oas.Servers {
+		l.server(fmt.Sprintf("/servers/%d", i), server)
+	}
+	if oas.Paths == nil && oas.Webhooks == nil && oas.Components == nil {
+		l.fail("", "at least one of paths, webhooks or components is required")
+	}
+	for _, path := range sortedKeys(oas.Paths) {
+		pointer := "/paths/" + escapePointer(path)
+		if !strings.HasPrefix(path, "/") {
+			l.fail(pointer, "path must begin with /")
+		}
+		l.pathItem(pointer, path, oas.Paths[path])
+	}
+	for _, name := range sortedKeys(oas.Webhooks) {
+		l.pathItem("/webhooks/"+escapePointer(name), "", oas.Webhooks[name])
+	}
+	l.components(oas.Components)
+	tags := make(map[string]bool)
+	for i, tag := range oas.Tags {
+		pointer := fmt.Sprintf("/tags/%d", i)
+		if tag == nil {
+			continue
+		}
+		if tag.Name == "" {
+			l.fail(pointer+"/name", "name is required")
+		} else if tags[tag.Name] {
+			l.fail(pointer+"/name", "duplicate tag "+tag.Name)
+		}
+		tags[tag.Name] = true
+		l.externalDocs(pointer+"/externalDocs", tag.ExternalDocs)
+	}
+	l.externalDocs("/externalDocs", oas.ExternalDocs)
+	return l.errs
+}
+
+func (l *linter) fail(pointer, message string) {
+	l.errs = append(l.errs, &ValidationError{Pointer: pointer, Message: message})
+}
+
+func (l *linter) info(info *Info) {
+	if info.Title == "" {
+		l.fail("/info/title", "title is required")
+	}
+	if info.Version == "" {
+		l.fail("/info/version", "version is required")
+	}
+	if info.License != nil {
+		if info.License.Name == "" {
+			l.fail("/info/license/name", "name is required")
+		}
+		if info.License.Identifier != "" && info.License.URL != "" {
+			l.fail("/info/license", "identifier and url are mutually exclusive")
+		}
+	}
+}
+
+func (l *linter) server(pointer string, server *Server) {
+	if server != nil && server.URL == "" {
+		l.fail(pointer+"/url", "url is required")
+	}
+}
+
+func (l *linter) externalDocs(pointer string, docs *ExternalDocumentation) {
+	if docs != nil && docs.URL == "" {
+		l.fail(pointer+"/url", "url is required")
+	}
+}
+
+//pathItem checks the operations of the path item, the template variables of the path are checked if path is set
+func (l *linter) pathItem(pointer, path string, item *PathItem) {
+	if item == nil || item.Ref != nil {
+		return
+	}
+	common := make([]*Parameter, len(item.Parameters))
+	for i := range item.Parameters {
+		common[i] = &item.Parameters[i]
+		l.parameter(fmt.Sprintf("%s/parameters/%d", pointer, i), common[i])
+	}
+	for i := range item.Servers {
+		l.server(fmt.Sprintf("%s/servers/%d", pointer, i), &item.Servers[i])
+	}
+	ops := item.Operations()
+	for _, method := range sortedKeys(ops) {
+		op := ops[method]
+		opPointer := pointer + "/" + strings.ToLower(method)
+		l.operation(opPointer, op)
+		if path != "" {
+			l.pathParameters(opPointer, path, append(l.resolveParameters(common), l.resolveParameters(op.Parameters)...))
+		}
+	}
+}
+
+func (l *linter) operation(pointer string, op *Operation) {
+	if op.OperationID != "" {
+		if first, ok := l.operationIds[op.OperationID]; ok {
+			l.fail(pointer+"/operationId", fmt.Sprintf("duplicate operationId %s also used at %s", op.OperationID, first))
+		} else {
+			l.operationIds[op.OperationID] = pointer + "/operationId"
+		}
+	}
+	seen := make(map[string]bool)
+	for i, p := range op.Parameters {
+		pp := fmt.Sprintf("%s/parameters/%d", pointer, i)
+		l.parameter(pp, p)
+		if p != nil && p.Ref == nil {
+			key := p.In + ":" + p.Name
+			if seen[key] {
+				l.fail(pp, fmt.Sprintf("duplicate %s parameter %s", p.In, p.Name))
+			}
+			seen[key] = true
+		}
+	}
+	if op.RequestBody != nil {
+		if len(op.RequestBody.Content) == 0 {
+			l.fail(pointer+"/requestBody/content", "content is required")
+		}
+		l.content(pointer+"/requestBody/content", op.RequestBody.Content)
+	}
+	for _, code := range sortedKeys(op.Responses) {
+		rp := pointer + "/responses/" + escapePointer(code)
+		if code != "default" && !validStatus(code) {
+			l.fail(rp, "invalid status code "+code)
+		}
+		l.response(rp, op.Responses[code])
+	}
+	l.externalDocs(pointer+"/externalDocs", op.ExternalDocs)
+	for i, server := range op.Servers {
+		l.server(fmt.Sprintf("%s/servers/%d", pointer, i), server)
+	}
+}
+
+//pathParameters checks that every template variable of the path has a path parameter and vice versa
+func (l *linter) pathParameters(pointer, path string, params []*Parameter) {
+	declared := make(map[string]bool)
+	for _, p := range params {
+		if p != nil && p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+	vars := make(map[string]bool)
+	for _, match := range pathVarExp.FindAllStringSubmatch(path, -1) {
+		vars[match[1]] = true
+		if !declared[match[1]] {
+			l.fail(pointer+"/parameters", "path template variable "+match[1]+" has no matching path parameter")
+		}
+	}
+	for _, name := range sortedKeys(declared) {
+		if !vars[name] {
+			l.fail(pointer+"/parameters", "path parameter "+name+" is not a variable of the path template")
+		}
+	}
+}
+
+//resolveParameters replaces the local parameter references with the parameter components
+func (l *linter) resolveParameters(params []*Parameter) []*Parameter {
+	resolved := make([]*Parameter, 0, len(params))
+	for _, p := range params {
+		if p != nil && p.Ref != nil {
+			name := strings.TrimPrefix(*p.Ref, componentPrefix+"parameters/")
+			if l.oas.Components != nil {
+				p = l.oas.Components.Parameters[unescapePointer(name)]
+			}
+		}
+		resolved = append(resolved, p)
+	}
+	return resolved
+}
+
+func (l *linter) parameter(pointer string, p *Parameter) {
+	if p == nil || p.Ref != nil {
+		return
+	}
+	if p.Name == "" {
+		l.fail(pointer+"/name", "name is required")
+	}
+	if p.In == "" {
+		l.fail(pointer+"/in", "in is required")
+	} else if !parameterLocations[p.In] {
+		l.fail(pointer+"/in", "in must be one of query, header, path or cookie but found "+p.In)
+	}
+	if p.In == "path" && !p.Required {
+		l.fail(pointer+"/required", "required must be true for the path parameters")
+	}
+	l.schemaOrContent(pointer, p.Schema, p.Content)
+	l.exampleOrExamples(pointer, p.Example.Value, p.Examples)
+}
+
+func (l *linter) header(pointer string, h *Header) {
+	if h == nil || h.Ref != nil {
+		return
+	}
+	l.schemaOrContent(pointer, h.Schema, h.Content)
+	l.exampleOrExamples(pointer, h.Example.Value, h.Examples)
+}
+
+func (l *linter) schemaOrContent(pointer string, schema *Schema, content map[string]MediaType) {
+	switch {
+	case schema != nil && len(content) > 0:
+		l.fail(pointer, "schema and content are mutually exclusive")
+	case schema == nil && len(content) == 0:
+		l.fail(pointer, "one of schema or content is required")
+	case len(content) > 1:
+		l.fail(pointer+"/content", "content must have exactly one entry")
+	}
+	l.content(pointer+"/content", content)
+}
+
+func (l *linter) exampleOrExamples(pointer string, example interface{}, examples map[string]Example) {
+	if example != nil && len(examples) > 0 {
+		l.fail(pointer, "example and examples are mutually exclusive")
+	}
+}
+
+func (l *linter) content(pointer string, content map[string]MediaType) {
+	for _, mediaType := range sortedKeys(content) {
+		mt := content[mediaType]
+		mp := pointer + "/" + escapePointer(mediaType)
+		l.exampleOrExamples(mp, mt.Example.Value, mt.Examples)
+		for _, name := range sortedKeys(mt.Encoding) {
+			for _, header := range sortedKeys(mt.Encoding[name].Headers) {
+				h := mt.Encoding[name].Headers[header]
+				l.header(mp+"/encoding/"+escapePointer(name)+"/headers/"+escapePointer(header), &h)
+			}
+		}
+	}
+}
+
+func (l *linter) response(pointer string, r *Response) {
+	if r == nil || r.Ref != nil {
+		return
+	}
+	if r.Description == "" {
+		l.fail(pointer+"/description", "description is required")
+	}
+	for _, name := range sortedKeys(r.Headers) {
+		h := r.Headers[name]
+		l.header(pointer+"/headers/"+escapePointer(name), &h)
+	}
+	l.content(pointer+"/content", r.Content)
+}
+
+func (l *linter) securityScheme(pointer string, s *SecurityScheme) {
+	if s == nil {
+		return
+	}
+	if s.Type == "" {
+		l.fail(pointer+"/type", "type is required")
+		return
+	}
+	if !securitySchemeTypes[s.Type] {
+		l.fail(pointer+"/type", "type must be one of apiKey, http, mutualTLS, oauth2 or openIdConnect but found "+s.Type)
+		return
+	}
+	switch s.Type {
+	case "apiKey":
+		if s.Name == "" {
+			l.fail(pointer+"/name", "name is required for the apiKey type")
+		}
+		if !apiKeyLocations[s.In] {
+			l.fail(pointer+"/in", "in must be one of query, header or cookie for the apiKey type")
+		}
+	case "http":
+		if s.Scheme == "" {
+			l.fail(pointer+"/scheme", "scheme is required for the http type")
+		}
+	case "oauth2":
+		if s.Flows == nil {
+			l.fail(pointer+"/flows", "flows is required for the oauth2 type")
+		}
+	case "openIdConnect":
+		if s.OpenIDConnectURL == "" {
+			l.fail(pointer+"/openIdConnectUrl", "openIdConnectUrl is required for the openIdConnect type")
+		}
+	}
+}
+
+func (l *linter) components(c *Components) {
+	if c == nil {
+		return
+	}
+	const pointer = "/components/"
+	names := func(kind string, keys []string) {
+		for _, name := range keys {
+			if !componentNameExp.MatchString(name) {
+				l.fail(pointer+kind+"/"+escapePointer(name), "component name must match "+componentNameExp.String())
+			}
+		}
+	}
+	names("schemas", sortedKeys(c.Schemas))
+	names("responses", sortedKeys(c.Responses))
+	for _, name := range sortedKeys(c.Responses) {
+		l.response(pointer+"responses/"+escapePointer(name), c.Responses[name])
+	}
+	names("parameters", sortedKeys(c.Parameters))
+	for _, name := range sortedKeys(c.Parameters) {
+		l.parameter(pointer+"parameters/"+escapePointer(name), c.Parameters[name])
+	}
+	names("examples", sortedKeys(c.Examples))
+	names("requestBodies", sortedKeys(c.RequestBodies))
+	names("headers", sortedKeys(c.Headers))
+	for _, name := range sortedKeys(c.Headers) {
+		l.header(pointer+"headers/"+escapePointer(name), c.Headers[name])
+	}
+	names("securitySchemes", sortedKeys(c.SecuritySchemes))
+	for _, name := range sortedKeys(c.SecuritySchemes) {
+		l.securityScheme(pointer+"securitySchemes/"+escapePointer(name), c.SecuritySchemes[name])
+	}
+	names("links", sortedKeys(c.Links))
+	names("callbacks", sortedKeys(c.Callbacks))
+	names("pathItems", sortedKeys(c.PathItems))
+	for _, name := range sortedKeys(c.PathItems) {
+		l.pathItem(pointer+"pathItems/"+escapePointer(name), "", c.PathItems[name])
+	}
+}
+
+//validStatus checks if the code is an HTTP status code or a range like 2XX
+func validStatus(code string) bool {
+	if len(code) != 3 || code[0] < '1' || code[0] > '5' {
+		return false
+	}
+	if strings.ToUpper(code[1:]) == "XX" {
+		return true
+	}
+	_, err := strconv.Atoi(code)
+	return err == nil
+}
+
+//sortedKeys returns the keys of the map in order so that the violations are reported in a stable order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]*PathItem:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Operation:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Response:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]MediaType:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]Encoding:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]Header:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Header:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Schema:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Parameter:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Example:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*RequestBody:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*SecurityScheme:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Link:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*Callback:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
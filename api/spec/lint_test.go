@@ -0,0 +1,153 @@
+package spec
+
+import (
+	"testing"
+)
+
+func TestOAS_Validate(t *testing.T) {
+	oas, err := ParseFile("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := oas.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() petstore = %v, want no errors", errs)
+	}
+	bundled, err := Bundle("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := bundled.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() bundled = %v, want no errors", errs)
+	}
+}
+
+func TestOAS_Validate_Violations(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		pointer string
+	}{
+		{
+			name:    "missing openapi",
+			doc:     "info: {title: t, version: v}\npaths: {}",
+			pointer: "/openapi",
+		},
+		{
+			name:    "missing title",
+			doc:     "openapi: 3.1.0\ninfo: {version: v}\npaths: {}",
+			pointer: "/info/title",
+		},
+		{
+			name:    "missing version",
+			doc:     "openapi: 3.1.0\ninfo: {title: t}\npaths: {}",
+			pointer: "/info/version",
+		},
+		{
+			name: "invalid parameter location",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: body, schema: {type: integer}}
+      responses: {200: {description: ok}}`,
+			pointer: "/paths/~1pets/get/parameters/0/in",
+		},
+		{
+			name: "invalid security scheme type",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+components:
+  securitySchemes:
+    basic: {type: basic}`,
+			pointer: "/components/securitySchemes/basic/type",
+		},
+		{
+			name: "duplicate operationId",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets:
+    get: {operationId: pets, responses: {200: {description: ok}}}
+    post: {operationId: pets, responses: {201: {description: ok}}}`,
+			pointer: "/paths/~1pets/post/operationId",
+		},
+		{
+			name: "undeclared path variable",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets/{petId}:
+    get: {responses: {200: {description: ok}}}`,
+			pointer: "/paths/~1pets~1{petId}/get/parameters",
+		},
+		{
+			name: "schema and content",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          schema: {type: string}
+          content: {application/json: {schema: {type: object}}}
+      responses: {200: {description: ok}}`,
+			pointer: "/paths/~1pets/get/parameters/0",
+		},
+		{
+			name: "example and examples",
+			doc: `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema: {type: object}
+              example: {}
+              examples: {empty: {value: {}}}`,
+			pointer: "/paths/~1pets/get/responses/200/content/application~1json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oas, err := Parse([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			errs := oas.Validate()
+			for _, e := range errs {
+				if e.Pointer == tt.pointer {
+					return
+				}
+			}
+			t.Errorf("Validate() = %v, want an error at %s", errs, tt.pointer)
+		})
+	}
+}
+
+func TestOAS_Validate_PathItemParameters(t *testing.T) {
+	doc := `openapi: 3.1.0
+info: {title: t, version: v}
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get: {responses: {200: {description: ok}}}
+components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, schema: {type: string}}`
+	oas, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := oas.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
@@ -21,13 +21,13 @@ type OAS struct {
 
 //Info as specified by OAS version 3.1.0 https://spec.openapis.org/oas/v3.1.0#info-object
 type Info struct {
-	Title          string  `json:"title" yaml:"title"` // Required
-	Summary        string  `json:"summary,omitempty" yaml:"summary,omitempty"`
-	Description    string  `json:"description,omitempty" yaml:"description,omitempty"`
-	TermsOfService string  `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
-	Contact        Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
-	License        License `json:"license,omitempty" yaml:"license,omitempty"`
-	Version        string  `json:"version" yaml:"version"` // Required
+	Title          string   `json:"title" yaml:"title"` // Required
+	Summary        string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
+	Contact        *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License        *License `json:"license,omitempty" yaml:"license,omitempty"`
+	Version        string   `json:"version" yaml:"version"` // Required
 }
 
 //Contact as specified by OAS version 3.1.0 https://spec.openapis.org/oas/v3.1.0#contact-object
@@ -46,9 +46,9 @@ type License struct {
 
 //Tag as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#tag-object
 type Tag struct {
-	Name         string                `json:"name" yaml:"name"` // Required
-	Description  string                `json:"description,omitempty" yaml:"description,omitempty"`
-	ExternalDocs ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Name         string                 `json:"name" yaml:"name"` // Required
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 }
 
 // Server as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#server-object
@@ -100,14 +100,14 @@ type Operation struct {
 
 //SecurityScheme as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#security-scheme-object
 type SecurityScheme struct {
-	Type             string     `json:"type" yaml:"type"` //Required Enum Values ( apiKey,http,oauth2,openIdConnect)
-	Description      string     `json:"description,omitempty" yaml:"description,omitempty"`
-	Name             string     `json:"name" yaml:"name"`     //Required
-	In               string     `json:"in" yaml:"in"`         //Required Valid values are "query", "header" or "cookie".
-	Scheme           string     `json:"scheme" yaml:"scheme"` //Required
-	BearerFormat     string     `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
-	Flows            OauthFlows `json:"flows" yaml:"flows"`                       //Required
-	OpenIDConnectURL string     `json:"openIdConnectUrl" yaml:"openIdConnectUrl"` //Required
+	Type             string      `json:"type" yaml:"type"` //Required Enum Values ( apiKey,http,oauth2,openIdConnect)
+	Description      string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Name             string      `json:"name" yaml:"name"`     //Required
+	In               string      `json:"in" yaml:"in"`         //Required Valid values are "query", "header" or "cookie".
+	Scheme           string      `json:"scheme" yaml:"scheme"` //Required
+	BearerFormat     string      `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+	Flows            *OauthFlows `json:"flows,omitempty" yaml:"flows,omitempty"`   //Required
+	OpenIDConnectURL string      `json:"openIdConnectUrl" yaml:"openIdConnectUrl"` //Required
 }
 
 //SecurityRequirement as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#security-requirement-object
@@ -117,10 +117,10 @@ type SecurityRequirement struct {
 
 // OauthFlows as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#oauth-flow-object
 type OauthFlows struct {
-	Implicit          OauthFlow `json:"implicit,omitempty" yaml:"implicit,omitempty"`
-	Password          OauthFlow `json:"password,omitempty" yaml:"password,omitempty"`
-	ClientCredentials OauthFlow `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
-	AuthorizationCode OauthFlow `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+	Implicit          *OauthFlow `json:"implicit,omitempty" yaml:"implicit,omitempty"`
+	Password          *OauthFlow `json:"password,omitempty" yaml:"password,omitempty"`
+	ClientCredentials *OauthFlow `json:"clientCredentials,omitempty" yaml:"clientCredentials,omitempty"`
+	AuthorizationCode *OauthFlow `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
 }
 
 // OauthFlow as specified by OAS version 3.0.3 https://spec.openapis.org/oas/v3.1.0#oauth-flow-object
@@ -154,7 +154,7 @@ type Parameter struct {
 	// in=query or in=cookie => style=form,
 	// in=path or in=header  => style=simple
 	Style         string `json:"style,omitempty" yaml:"style,omitempty"`
-	Explode       bool   `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Explode       *bool  `json:"explode,omitempty" yaml:"explode,omitempty"`
 	AllowReserved bool   `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
 
 	//Example and Examples are mutually exclusive
@@ -163,13 +163,12 @@ type Parameter struct {
 
 	//Schema and content Type are mutually exclusive
 	Content map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
-	Schema  Schema               `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Schema  *Schema              `json:"schema,omitempty" yaml:"schema,omitempty"`
 }
 
 //Header type as specified by OAS version 3.0.3
 type Header struct {
-	//	Either Ref or Name will be present.
-	Reference
+	//	Either Ref or Name will be present. The Ref is promoted from the embedded Example.
 	Description     string `json:"description,omitempty" yaml:"description,omitempty" `
 	Required        bool   `json:"required" yaml:"required"`
 	Deprecated      bool   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
@@ -196,13 +195,14 @@ type Header struct {
 
 	//Schema and content Type are mutually exclusive
 	Content map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
-	Schema  Schema               `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Schema  *Schema              `json:"schema,omitempty" yaml:"schema,omitempty"`
 }
 
 //Schema Object
 
 //RequestBody object as per https://spec.openapis.org/oas/v3.1.0#requestBodyObject
 type RequestBody struct {
+	Reference
 	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
 	Required    bool                 `json:"required,omitempty" yaml:"required,omitempty"`
@@ -225,7 +225,7 @@ type Link struct {
 	Parameters   map[string]interface{} `json:"parameters,omitempty," yaml:"parameters,omitempty"`
 	RequestBody  interface{}            `json:"requestBody,omitempty," yaml:"requestBody,omitempty"`
 	Description  string                 `json:"description,omitempty," yaml:"description,omitempty"`
-	Server       Server                 `json:"server,omitempty," yaml:"server,omitempty"`
+	Server       *Server                `json:"server,omitempty," yaml:"server,omitempty"`
 }
 
 //Callback struct to hold the
@@ -250,7 +250,7 @@ type Components struct {
 
 //MediaType object  as per OAS 3.0.3
 type MediaType struct {
-	Schema Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
 	Example
 	Examples map[string]Example  `json:"examples,omitempty" yaml:"examples,omitempty"` //Example and Examples are mutually exclusive
 	Encoding map[string]Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
@@ -287,51 +287,53 @@ type Reference struct {
 	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
+//Extension holds the specification extensions (x- prefixed fields)
 type Extension map[string]interface{}
 
 //Schema Object for
 type Schema struct {
 	Reference
-	ID                   string                `json:"id,omitempty" yaml:"id,omitempty"`
-	Schema               string                `json:"-" yaml:"-"`
-	Description          string                `json:"description,omitempty" yaml:"description,omitempty"`
-	Type                 string                `json:"type,omitempty" yaml:"type,omitempty"`
-	Nullable             bool                  `json:"nullable,omitempty" yaml:"nullable,omitempty"`
-	Format               *string               `json:"format,omitempty" yaml:"format,omitempty"`
-	Title                string                `json:"title,omitempty" yaml:"title,omitempty"`
-	Default              interface{}           `json:"default,omitempty" yaml:"default,omitempty"`
-	Maximum              *float64              `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	ExclusiveMaximum     *float64              `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
-	Minimum              *float64              `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	ExclusiveMinimum     *float64              `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	MaxLength            *int                  `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
-	MinLength            *int                  `json:"minLength,omitempty" yaml:"minLength,omitempty"`
-	Pattern              *string               `json:"pattern,omitempty" yaml:"pattern,omitempty"`
-	MaxItems             *int                  `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
-	MinItems             *int                  `json:"minItems,omitempty" yaml:"minItems,omitempty"`
-	UniqueItems          bool                  `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
-	MultipleOf           *float64              `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
-	Enum                 []interface{}         `json:"enum,omitempty" yaml:"enum,omitempty"`
-	MaxProperties        *int                  `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
-	MinProperties        *int                  `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
-	Required             []string              `json:"required,omitempty" yaml:"required,omitempty"`
-	Items                *Schema               `json:"items,omitempty" yaml:"items,omitempty"`
-	AllOf                []*Schema             `json:"allOf,omitempty" yaml:"allOf,omitempty"`
-	OneOf                []*Schema             `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
-	AnyOf                []*Schema             `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
-	Not                  *Schema               `json:"not,omitempty" yaml:"not,omitempty"`
-	Properties           map[string]*Schema    `json:"properties,omitempty" yaml:"properties,omitempty"`
-	AdditionalProperties interface{}           `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
-	AdditionalItems      *Schema               `json:"additionalItems,omitempty" yaml:"additionalItems,omitempty"`
-	Xml                  *Xml                  `json:"xml,omitempty" yaml:"xml,omitempty"`
-	ReadOnly             bool                  `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
-	WriteOnly            bool                  `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
-	Discriminator        Discriminator         `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
-	ExternalDocs         ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
-	Example              interface{}           `json:"example,omitempty" yaml:"example,omitempty"`
-	Examples             []interface{}         `json:"examples,omitempty" yaml:"examples,omitempty"`
-	Deprecated           bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
-	Extension
+	ID                   string                 `json:"id,omitempty" yaml:"id,omitempty"`
+	Schema               string                 `json:"-" yaml:"-"`
+	Description          string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Format               *string                `json:"format,omitempty" yaml:"format,omitempty"`
+	Title                string                 `json:"title,omitempty" yaml:"title,omitempty"`
+	Default              interface{}            `json:"default,omitempty" yaml:"default,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern              *string                `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MaxProperties        *int                   `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+	MinProperties        *int                   `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
+	Required             []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *Schema                `json:"items,omitempty" yaml:"items,omitempty"`
+	AllOf                []*Schema              `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	OneOf                []*Schema              `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema              `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Not                  *Schema                `json:"not,omitempty" yaml:"not,omitempty"`
+	Properties           map[string]*Schema     `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	AdditionalItems      *Schema                `json:"additionalItems,omitempty" yaml:"additionalItems,omitempty"`
+	Xml                  *Xml                   `json:"xml,omitempty" yaml:"xml,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	WriteOnly            bool                   `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
+	Discriminator        *Discriminator         `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+	ExternalDocs         *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Example              interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples             []interface{}          `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	//Extension holds the x- prefixed fields, these are inlined by MarshalJSON
+	Extension `json:"-" yaml:"-"`
 }
 
 type Xml struct {
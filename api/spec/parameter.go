@@ -0,0 +1,44 @@
+package spec
+
+//Parameter styles as per https://spec.openapis.org/oas/v3.1.0#style-values
+const (
+	StyleMatrix         = "matrix"
+	StyleLabel          = "label"
+	StyleForm           = "form"
+	StyleSimple         = "simple"
+	StyleSpaceDelimited = "spaceDelimited"
+	StylePipeDelimited  = "pipeDelimited"
+	StyleDeepObject     = "deepObject"
+)
+
+//StyleOrDefault returns the style of the parameter defaulting to form for the query and cookie parameters and to
+//simple for the path and header parameters.
+func (p *Parameter) StyleOrDefault() string {
+	if p.Style != "" {
+		return p.Style
+	}
+	if p.In == "query" || p.In == "cookie" {
+		return StyleForm
+	}
+	return StyleSimple
+}
+
+//Exploded returns the explode value of the parameter, when not set it defaults to true for the form style and to
+//false for the other styles.
+func (p *Parameter) Exploded() bool {
+	if p.Explode != nil {
+		return *p.Explode
+	}
+	return p.StyleOrDefault() == StyleForm
+}
+
+//Delimiter returns the separator of the array values of a non exploded parameter
+func (p *Parameter) Delimiter() string {
+	switch p.StyleOrDefault() {
+	case StyleSpaceDelimited:
+		return " "
+	case StylePipeDelimited:
+		return "|"
+	}
+	return ","
+}
@@ -0,0 +1,92 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+//Parse reads an OAS document in either the JSON or the YAML format
+func Parse(data []byte) (*OAS, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		var err error
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	oas := &OAS{}
+	if err := json.Unmarshal(data, oas); err != nil {
+		return nil, err
+	}
+	return oas, nil
+}
+
+//ParseFile reads the OAS document from the file at the given path
+func ParseFile(path string) (*OAS, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	oas, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return oas, nil
+}
+
+//yamlToJSON converts a YAML document to JSON so that the json tags are used for decoding both the formats
+func yamlToJSON(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	value, err := nodeValue(&node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+//nodeValue converts the YAML node to a value that can be marshalled to JSON.
+//Mapping keys are always read as strings since YAML allows keys like the unquoted response codes.
+func nodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			v, err := nodeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	default:
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
@@ -0,0 +1,55 @@
+package spec
+
+import (
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	oas, err := ParseFile("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oas.Info.Title != "Petstore" || oas.OpenAPI != Version {
+		t.Errorf("ParseFile() info = %+v", oas.Info)
+	}
+	if len(oas.Paths) != 2 {
+		t.Fatalf("ParseFile() paths = %v, want 2", len(oas.Paths))
+	}
+	list := oas.Paths["/pets"].Get
+	if list == nil || list.OperationID != "listPets" || list.Parameters[0].Schema.Type != "integer" {
+		t.Fatalf("ParseFile() listPets = %+v", list)
+	}
+	if max := list.Parameters[0].Schema.Maximum; max == nil || *max != 100 {
+		t.Errorf("ParseFile() maximum = %v", max)
+	}
+	if _, ok := list.Responses["200"]; !ok {
+		t.Errorf("ParseFile() responses = %v", list.Responses)
+	}
+	if ref := oas.Paths["/pets/{petId}"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref == nil || *ref != "#/components/schemas/Pet" {
+		t.Errorf("ParseFile() ref = %v", ref)
+	}
+	if len(oas.Security) != 1 || oas.Security[0].Fields["api_key"] == nil {
+		t.Errorf("ParseFile() security = %+v", oas.Security)
+	}
+
+	data, err := oas.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Paths) != 2 || again.Components.Schemas["Pet"].Properties["id"].Type != "integer" {
+		t.Errorf("Parse() JSON round trip = %+v", again)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse([]byte("openapi: [3.1.0")); err == nil {
+		t.Error("Parse() expected an error for invalid YAML")
+	}
+	if _, err := Parse([]byte(`{"openapi": 3}`)); err == nil {
+		t.Error("Parse() expected an error for invalid field types")
+	}
+}
@@ -0,0 +1,52 @@
+package spec
+
+import "strings"
+
+//Version of the OpenAPI specification modelled by this package
+const Version = "3.1.0"
+
+//Operations returns the operations defined on the path item keyed by the upper case HTTP method
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"GET":     p.Get,
+		"PUT":     p.Put,
+		"POST":    p.Post,
+		"DELETE":  p.Delete,
+		"OPTIONS": p.Options,
+		"HEAD":    p.Head,
+		"PATCH":   p.Patch,
+		"TRACE":   p.Trace,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+//SetOperation sets the operation for the HTTP method on the path item.
+//Returns false if the OAS path item has no field for the method.
+func (p *PathItem) SetOperation(method string, op *Operation) bool {
+	switch strings.ToUpper(method) {
+	case "GET":
+		p.Get = op
+	case "PUT":
+		p.Put = op
+	case "POST":
+		p.Post = op
+	case "DELETE":
+		p.Delete = op
+	case "OPTIONS":
+		p.Options = op
+	case "HEAD":
+		p.Head = op
+	case "PATCH":
+		p.Patch = op
+	case "TRACE":
+		p.Trace = op
+	default:
+		return false
+	}
+	return true
+}
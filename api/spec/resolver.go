@@ -0,0 +1,590 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//ErrCyclicReference is returned when the references cannot be dereferenced as they form a cycle without any value
+//between them
+var ErrCyclicReference = errors.New("cyclic reference")
+
+const (
+	refKey          = "$ref"
+	componentsKey   = "components"
+	componentPrefix = "#/components/"
+)
+
+//componentKinds maps the keys of the document to the kind of the component their values are
+var componentKinds = map[string]string{
+	"schema":               "schemas",
+	"items":                "schemas",
+	"not":                  "schemas",
+	"additionalProperties": "schemas",
+	"additionalItems":      "schemas",
+	"properties":           "schemas",
+	"allOf":                "schemas",
+	"anyOf":                "schemas",
+	"oneOf":                "schemas",
+	"schemas":              "schemas",
+	"parameters":           "parameters",
+	"responses":            "responses",
+	"requestBody":          "requestBodies",
+	"requestBodies":        "requestBodies",
+	"headers":              "headers",
+	"examples":             "examples",
+	"links":                "links",
+	"callbacks":            "callbacks",
+	"securitySchemes":      "securitySchemes",
+	"paths":                "pathItems",
+	"pathItems":            "pathItems",
+	"webhooks":             "pathItems",
+}
+
+//nameMaps are the keys of the document whose values are maps keyed by names instead of the field names
+var nameMaps = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"$defs":             true,
+	"schemas":           true,
+	"parameters":        true,
+	"responses":         true,
+	"requestBodies":     true,
+	"headers":           true,
+	"examples":          true,
+	"links":             true,
+	"callbacks":         true,
+	"securitySchemes":   true,
+	"pathItems":         true,
+	"paths":             true,
+	"webhooks":          true,
+	"content":           true,
+	"encoding":          true,
+}
+
+//resolver loads the documents referred by the $ref and brings them into a single document
+type resolver struct {
+	//docs loaded by their absolute path
+	docs map[string]interface{}
+	//root document the references are bundled into
+	root map[string]interface{}
+	//rootFile is the absolute path of the root document
+	rootFile string
+	//bundled maps the external <file>#<pointer> targets to their local references in the root document
+	bundled map[string]string
+}
+
+//Bundle loads the OAS document at the path and brings every component referred from other files into the components
+//of the document. The references of the returned document are all local (#/components/...).
+//Remote (http) references are not supported.
+func Bundle(path string) (*OAS, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{docs: make(map[string]interface{}), bundled: make(map[string]string), rootFile: abs}
+	doc, err := r.load(abs)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an OAS document", path)
+	}
+	r.root = root
+	if err = r.bundleComponents(); err != nil {
+		return nil, err
+	}
+	if err = r.bundle(root, abs, "", false); err != nil {
+		return nil, err
+	}
+	return fromGeneric(root)
+}
+
+//Dereference returns a copy of the document with every local reference replaced by its target.
+//The summary and description of a reference override the ones of its target.
+//The references of a recursive schema, such as a node whose children refer back to it, are kept where the recursion
+//starts again. Returns an error wrapping ErrCyclicReference if the references form a cycle without any value between
+//them such as A -> B -> A.
+func (oas *OAS) Dereference() (*OAS, error) {
+	doc, err := toGeneric(oas)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := dereference(doc, doc, nil)
+	if err != nil {
+		return nil, err
+	}
+	return fromGeneric(resolved)
+}
+
+//ResolveSchema follows the local references of the schema till a schema without a reference is found
+func (oas *OAS) ResolveSchema(s *Schema) (*Schema, error) {
+	seen := make(map[string]bool)
+	for s != nil && s.Ref != nil {
+		ref := *s.Ref
+		if seen[ref] {
+			return nil, fmt.Errorf("%w: %s", ErrCyclicReference, ref)
+		}
+		seen[ref] = true
+		name := strings.TrimPrefix(ref, componentPrefix+"schemas/")
+		if name == ref || oas.Components == nil || oas.Components.Schemas[unescapePointer(name)] == nil {
+			return nil, fmt.Errorf("unable to resolve the schema reference %s", ref)
+		}
+		s = oas.Components.Schemas[unescapePointer(name)]
+	}
+	return s, nil
+}
+
+//Split writes the document to the directory with every component in its own file at <kind>/<name>.yaml and the
+//document at openapi.yaml. The references to the components are rewritten to relative file references so that the
+//files can be bundled back with Bundle.
+func (oas *OAS) Split(dir string) error {
+	doc, err := toGeneric(oas)
+	if err != nil {
+		return err
+	}
+	root := doc.(map[string]interface{})
+	components, _ := root[componentsKey].(map[string]interface{})
+	kinds := make([]string, 0, len(components))
+	for kind := range components {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		entries, ok := components[kind].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Join(dir, kind), 0755); err != nil {
+			return err
+		}
+		for name, entry := range entries {
+			file := filepath.Join(kind, name+".yaml")
+			if err = writeYAML(filepath.Join(dir, file), relativeRefs(entry, kind)); err != nil {
+				return err
+			}
+			entries[name] = map[string]interface{}{refKey: filepath.ToSlash(file)}
+		}
+	}
+	//the components are already file references, rewrite the refs of the rest of the document
+	delete(root, componentsKey)
+	relativeRefs(root, "")
+	if components != nil {
+		root[componentsKey] = components
+	}
+	return writeYAML(filepath.Join(dir, "openapi.yaml"), root)
+}
+
+//load reads the document at the absolute path, the documents are cached by their path
+func (r *resolver) load(path string) (interface{}, error) {
+	if doc, ok := r.docs[path]; ok {
+		return doc, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' && data[0] != '[' {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", path, err)
+		}
+	}
+	doc, err := decodeGeneric(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	r.docs[path] = doc
+	return doc, nil
+}
+
+//bundleComponents replaces the components of the root document that are file references with their content.
+//All the components are registered before their content is walked so that they can refer to each other.
+func (r *resolver) bundleComponents() error {
+	type component struct {
+		kind, file string
+		content    interface{}
+	}
+	var bundled []component
+	components, _ := r.root[componentsKey].(map[string]interface{})
+	for kind, value := range components {
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, entry := range entries {
+			ref, ok := refOf(entry)
+			if !ok || strings.HasPrefix(ref, "#") {
+				continue
+			}
+			file, pointer, target, err := r.target(ref, r.rootFile)
+			if err != nil {
+				return err
+			}
+			r.bundled[file+"#"+pointer] = componentPrefix + kind + "/" + escapePointer(name)
+			content := deepCopy(target)
+			entries[name] = content
+			bundled = append(bundled, component{kind: kind, file: file, content: content})
+		}
+	}
+	for _, c := range bundled {
+		if err := r.bundle(c.content, c.file, c.kind, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//bundle walks the value of the file rewriting the external references to the bundled components.
+//The kind is the component type of the value and names is set if the keys of the value are names.
+func (r *resolver) bundle(value interface{}, file, kind string, names bool) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := refOf(v); ok && !names {
+			local, err := r.bundleRef(ref, file, kind)
+			if err != nil {
+				return err
+			}
+			v[refKey] = local
+			return nil
+		}
+		for key, child := range v {
+			childKind, childNames := kind, false
+			if !names {
+				if k, ok := componentKinds[key]; ok {
+					childKind = k
+				}
+				childNames = nameMaps[key]
+			}
+			if err := r.bundle(child, file, childKind, childNames); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if err := r.bundle(child, file, kind, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//bundleRef returns the local reference for the ref found in the file, the targets in the other files are copied to
+//the components of the root document
+func (r *resolver) bundleRef(ref, file, kind string) (string, error) {
+	if strings.HasPrefix(ref, "#") && file == r.rootFile {
+		return ref, nil
+	}
+	targetFile, pointer, target, err := r.target(ref, file)
+	if err != nil {
+		return "", err
+	}
+	key := targetFile + "#" + pointer
+	if local, ok := r.bundled[key]; ok {
+		return local, nil
+	}
+	if targetFile == r.rootFile {
+		return "#" + pointer, nil
+	}
+	tokens := splitPointer(pointer)
+	name := strings.TrimSuffix(filepath.Base(targetFile), filepath.Ext(targetFile))
+	if len(tokens) > 0 {
+		name = tokens[len(tokens)-1]
+	}
+	if len(tokens) == 3 && tokens[0] == componentsKey {
+		kind = tokens[1]
+	}
+	if kind == "" {
+		return "", fmt.Errorf("unable to find the component type of the reference %s in %s", ref, file)
+	}
+	components, _ := r.root[componentsKey].(map[string]interface{})
+	if components == nil {
+		components = make(map[string]interface{})
+		r.root[componentsKey] = components
+	}
+	entries, _ := components[kind].(map[string]interface{})
+	if entries == nil {
+		entries = make(map[string]interface{})
+		components[kind] = entries
+	}
+	unique := name
+	for i := 2; entries[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	local := componentPrefix + kind + "/" + escapePointer(unique)
+	r.bundled[key] = local
+	content := deepCopy(target)
+	//reserve the name before walking the content so that the cyclic references resolve to it
+	entries[unique] = content
+	if err = r.bundle(content, targetFile, kind, false); err != nil {
+		return "", err
+	}
+	return local, nil
+}
+
+//target finds the file, the pointer and the value the ref found in the file refers to
+func (r *resolver) target(ref, file string) (string, string, interface{}, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return "", "", nil, fmt.Errorf("remote reference %s is not supported", ref)
+	}
+	targetFile, pointer := file, ref
+	if i := strings.Index(ref, "#"); i >= 0 {
+		pointer = ref[i+1:]
+		if i > 0 {
+			targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(ref[:i]))
+		}
+	} else {
+		pointer = ""
+		targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(ref))
+	}
+	doc, err := r.load(targetFile)
+	if err != nil {
+		return "", "", nil, err
+	}
+	target, err := lookup(doc, pointer)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("unable to resolve %s in %s: %w", ref, file, err)
+	}
+	return targetFile, pointer, target, nil
+}
+
+//dereference replaces the local references in the value with their targets in the doc
+func dereference(doc, value interface{}, stack []string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := refOf(v); ok {
+			if !strings.HasPrefix(ref, "#") {
+				return nil, fmt.Errorf("reference %s is not local, bundle the document before dereferencing", ref)
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != ref {
+					continue
+				}
+				//a recursive schema such as a node having children of its type keeps the reference
+				for _, s := range stack[i:] {
+					if s == "" {
+						return v, nil
+					}
+				}
+				return nil, fmt.Errorf("%w: %s -> %s", ErrCyclicReference, strings.Join(refsOf(stack), " -> "), ref)
+			}
+			target, err := lookup(doc, ref[1:])
+			if err != nil {
+				return nil, fmt.Errorf("unable to resolve %s: %w", ref, err)
+			}
+			resolved, err := dereference(doc, target, append(stack[:len(stack):len(stack)], ref))
+			if err != nil {
+				return nil, err
+			}
+			if m, ok := resolved.(map[string]interface{}); ok {
+				resolved = overrideRef(m, v)
+			}
+			return resolved, nil
+		}
+		m := make(map[string]interface{}, len(v))
+		//the empty entry marks a value between the references, the references around it are not a pure cycle
+		if len(stack) > 0 && stack[len(stack)-1] != "" {
+			stack = append(stack[:len(stack):len(stack)], "")
+		}
+		for key, child := range v {
+			resolved, err := dereference(doc, child, stack)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = resolved
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, child := range v {
+			resolved, err := dereference(doc, child, stack)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = resolved
+		}
+		return s, nil
+	}
+	return value, nil
+}
+
+//refsOf returns the references of the stack without the marks of the values between them
+func refsOf(stack []string) []string {
+	refs := make([]string, 0, len(stack))
+	for _, s := range stack {
+		if s != "" {
+			refs = append(refs, s)
+		}
+	}
+	return refs
+}
+
+//overrideRef copies the target applying the summary and description of the reference
+func overrideRef(target, ref map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(target))
+	for k, v := range target {
+		m[k] = v
+	}
+	for _, key := range []string{"summary", "description"} {
+		if v, ok := ref[key]; ok {
+			m[key] = v
+		}
+	}
+	return m
+}
+
+//relativeRefs rewrites the component references in the value to the files written by Split, the value is in the
+//directory of the kind
+func relativeRefs(value interface{}, kind string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := refOf(v); ok && strings.HasPrefix(ref, componentPrefix) {
+			tokens := splitPointer(ref[1:])
+			if len(tokens) == 3 {
+				file := tokens[1] + "/" + tokens[2] + ".yaml"
+				if kind != "" {
+					file = "../" + file
+					if tokens[1] == kind {
+						file = tokens[2] + ".yaml"
+					}
+				}
+				v[refKey] = file
+			}
+			return v
+		}
+		for _, child := range v {
+			relativeRefs(child, kind)
+		}
+	case []interface{}:
+		for _, child := range v {
+			relativeRefs(child, kind)
+		}
+	}
+	return value
+}
+
+//lookup finds the value at the JSON pointer in the doc
+func lookup(doc interface{}, pointer string) (interface{}, error) {
+	value := doc
+	for _, token := range splitPointer(pointer) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			value = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+	return value, nil
+}
+
+//splitPointer splits the JSON pointer to its unescaped reference tokens
+func splitPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointer(token)
+	}
+	return tokens
+}
+
+//unescapePointer unescapes a JSON pointer reference token as per RFC 6901
+func unescapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+//refOf returns the $ref of the value if it is a reference object
+func refOf(value interface{}) (string, bool) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	ref, ok := m[refKey].(string)
+	return ref, ok
+}
+
+//deepCopy copies the maps and the slices of the generic value
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			m[key] = deepCopy(child)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, child := range v {
+			s[i] = deepCopy(child)
+		}
+		return s
+	}
+	return value
+}
+
+//toGeneric converts the document to its generic map form
+func toGeneric(oas *OAS) (interface{}, error) {
+	data, err := json.Marshal(oas)
+	if err != nil {
+		return nil, err
+	}
+	return decodeGeneric(data)
+}
+
+//fromGeneric converts the generic map form to the document
+func fromGeneric(doc interface{}) (*OAS, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	oas := &OAS{}
+	if err = json.Unmarshal(data, oas); err != nil {
+		return nil, err
+	}
+	return oas, nil
+}
+
+//decodeGeneric decodes the JSON retaining the numbers as json.Number
+func decodeGeneric(data []byte) (interface{}, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+//writeYAML writes the generic value to the file in the YAML format
+func writeYAML(path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if data, err = jsonToYAML(data); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,172 @@
+package spec
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	oas, err := Bundle("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := oas.Components.Schemas
+	for _, name := range []string{"Pet", "Owner", "Address"} {
+		if schemas[name] == nil {
+			t.Fatalf("Bundle() schema %s missing in %v", name, schemas)
+		}
+	}
+	if len(schemas) != 3 {
+		t.Errorf("Bundle() schemas = %v, want 3", len(schemas))
+	}
+	pet := oas.Paths["/pets/{petId}"].Get
+	refs := []struct {
+		got  *string
+		want string
+	}{
+		{pet.Responses["200"].Content["application/json"].Schema.Ref, "#/components/schemas/Pet"},
+		{pet.Parameters[0].Ref, "#/components/parameters/PetId"},
+		{oas.Paths["/owners"].Get.Responses["200"].Content["application/json"].Schema.Items.Ref, "#/components/schemas/Owner"},
+		{schemas["Pet"].Properties["owner"].Ref, "#/components/schemas/Owner"},
+		{schemas["Pet"].Properties["parent"].Ref, "#/components/schemas/Pet"},
+		{schemas["Owner"].Properties["address"].Ref, "#/components/schemas/Address"},
+	}
+	for _, ref := range refs {
+		if ref.got == nil || *ref.got != ref.want {
+			t.Errorf("Bundle() ref = %v, want %v", ref.got, ref.want)
+		}
+	}
+	if p := oas.Components.Parameters["PetId"]; p == nil || p.Name != "petId" || p.In != "path" {
+		t.Errorf("Bundle() parameter = %+v", p)
+	}
+}
+
+func TestOAS_Dereference(t *testing.T) {
+	oas, err := Bundle("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := oas.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := resolved.Paths["/pets/{petId}"].Get
+	if get.Parameters[0].Ref != nil || get.Parameters[0].Name != "petId" {
+		t.Errorf("Dereference() parameter = %+v", get.Parameters[0])
+	}
+	pet := get.Responses["200"].Content["application/json"].Schema
+	if pet.Ref != nil || pet.Properties["owner"].Properties["address"].Properties["city"].Type != TypeString {
+		t.Errorf("Dereference() schema = %+v", pet)
+	}
+}
+
+func TestOAS_DereferenceCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas string
+		cyclic  bool
+	}{
+		{
+			name:    "recursive schema",
+			schemas: "    Node:\n      type: object\n      properties:\n        children: {type: array, items: {$ref: '#/components/schemas/Node'}}\n",
+		},
+		{
+			name:    "mutually recursive schemas",
+			schemas: "    A:\n      properties: {b: {$ref: '#/components/schemas/B'}}\n    B:\n      properties: {a: {$ref: '#/components/schemas/A'}}\n",
+		},
+		{
+			name:    "cycle of references",
+			schemas: "    A: {$ref: '#/components/schemas/B'}\n    B: {$ref: '#/components/schemas/A'}\n",
+			cyclic:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oas, err := Parse([]byte("openapi: 3.1.0\ninfo: {title: Tree, version: 1.0.0}\npaths: {}\ncomponents:\n  schemas:\n" + tt.schemas))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved, err := oas.Dereference()
+			if tt.cyclic {
+				if !errors.Is(err, ErrCyclicReference) {
+					t.Errorf("Dereference() error = %v, want ErrCyclicReference", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, schema := range resolved.Components.Schemas {
+				if schema.Ref != nil {
+					t.Errorf("Dereference() %s = %+v, want the schema", name, schema)
+				}
+			}
+		})
+	}
+	oas, _ := Parse([]byte("openapi: 3.1.0\ninfo: {title: Tree, version: 1.0.0}\npaths: {}\ncomponents:\n  schemas:\n" + tests[0].schemas))
+	resolved, err := oas.Dereference()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the node is expanded once below itself, the recursion then stops at the reference back to it
+	children := resolved.Components.Schemas["Node"].Properties["children"].Items
+	if children.Ref != nil || children.Type != TypeObject {
+		t.Fatalf("Dereference() children items = %+v", children)
+	}
+	if items := children.Properties["children"].Items; items.Ref == nil || *items.Ref != "#/components/schemas/Node" {
+		t.Errorf("Dereference() grandchildren items = %+v", items)
+	}
+}
+
+func TestOAS_ResolveSchema(t *testing.T) {
+	oas, err := Bundle("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := oas.ResolveSchema(oas.Components.Schemas["Pet"].Properties["owner"])
+	if err != nil || s != oas.Components.Schemas["Owner"] {
+		t.Errorf("ResolveSchema() = %v, %v", s, err)
+	}
+	ref := "#/components/schemas/Missing"
+	if _, err = oas.ResolveSchema(&Schema{Reference: Reference{Ref: &ref}}); err == nil {
+		t.Error("ResolveSchema() expected an error for a missing schema")
+	}
+}
+
+func TestOAS_Split(t *testing.T) {
+	oas, err := Bundle("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "oas-split")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = oas.Split(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"openapi.yaml", "schemas/Pet.yaml", "schemas/Owner.yaml", "schemas/Address.yaml", "parameters/PetId.yaml"} {
+		if _, err = os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Split() file %s: %v", file, err)
+		}
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "schemas/Pet.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "$ref: Owner.yaml"; !strings.Contains(string(data), want) {
+		t.Errorf("Split() Pet.yaml = %s, want %s", data, want)
+	}
+	bundled, err := Bundle(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundled.Components.Schemas) != 3 || *bundled.Components.Schemas["Pet"].Properties["owner"].Ref != "#/components/schemas/Owner" {
+		t.Errorf("Bundle() of the split document = %+v", bundled.Components.Schemas)
+	}
+}
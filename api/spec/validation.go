@@ -0,0 +1,391 @@
+package spec
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+//ValidationError describes a single violation found during the validation
+type ValidationError struct {
+	//Pointer is the JSON pointer to the location of the violation
+	Pointer string `json:"pointer"`
+	//Message describing the violation
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return e.Pointer + ": " + e.Message
+}
+
+//Schema types as per https://json-schema.org/draft/2020-12/json-schema-core.html#name-instance-data-model
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeObject  = "object"
+	TypeNull    = "null"
+)
+
+var (
+	//patterns caches the compiled schema patterns
+	patterns = sync.Map{}
+	uuidExp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+//ValidateValue validates a decoded JSON value against the schema.
+//The value is expected in the form produced by encoding/json i.e. map[string]interface{}, []interface{},
+//string, float64 or json.Number, bool and nil. References are not followed, use OAS.Dereference before validation.
+func (s *Schema) ValidateValue(value interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	if s == nil || s.Ref != nil {
+		return errs
+	}
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	if value == nil {
+		if s.Type != "" && s.Type != TypeNull && !s.Nullable {
+			fail("value must not be null")
+		}
+		return errs
+	}
+	if s.Type != "" && !isType(value, s.Type) {
+		fail("expected type %s but found %s", s.Type, typeOf(value))
+		return errs
+	}
+	if len(s.Enum) > 0 && !inEnum(value, s.Enum) {
+		fail("value must be one of %v", s.Enum)
+	}
+	switch v := value.(type) {
+	case string:
+		errs = append(errs, s.validateString(v, pointer)...)
+	case []interface{}:
+		errs = append(errs, s.validateArray(v, pointer)...)
+	case map[string]interface{}:
+		errs = append(errs, s.validateObject(v, pointer)...)
+	case bool:
+	default:
+		if n, ok := toFloat(value); ok {
+			errs = append(errs, s.validateNumber(n, pointer)...)
+		}
+	}
+	for _, sub := range s.AllOf {
+		errs = append(errs, sub.ValidateValue(value, pointer)...)
+	}
+	if len(s.AnyOf) > 0 && countValid(s.AnyOf, value, pointer) == 0 {
+		fail("value must match at least one of the anyOf schemas")
+	}
+	if len(s.OneOf) > 0 {
+		if n := countValid(s.OneOf, value, pointer); n != 1 {
+			fail("value must match exactly one of the oneOf schemas but matched %d", n)
+		}
+	}
+	if s.Not != nil && len(s.Not.ValidateValue(value, pointer)) == 0 {
+		fail("value must not match the not schema")
+	}
+	return errs
+}
+
+func (s *Schema) validateString(v, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	length := utf8.RuneCountInString(v)
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("length must be >= %d", *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("length must be <= %d", *s.MaxLength)
+	}
+	if s.Pattern != nil {
+		if exp, err := compilePattern(*s.Pattern); err != nil {
+			fail("invalid pattern %s: %v", *s.Pattern, err)
+		} else if !exp.MatchString(v) {
+			fail("value must match the pattern %s", *s.Pattern)
+		}
+	}
+	if s.Format != nil && !validFormat(*s.Format, v) {
+		fail("value is not a valid %s", *s.Format)
+	}
+	return errs
+}
+
+func (s *Schema) validateNumber(v float64, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.Minimum != nil && v < *s.Minimum {
+		fail("value must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		fail("value must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		fail("value must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		fail("value must be < %v", *s.ExclusiveMaximum)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf != 0 {
+		if q := v / *s.MultipleOf; q != math.Trunc(q) {
+			fail("value must be a multiple of %v", *s.MultipleOf)
+		}
+	}
+	if s.Format != nil {
+		switch *s.Format {
+		case "int32":
+			if v < math.MinInt32 || v > math.MaxInt32 || v != math.Trunc(v) {
+				fail("value is not a valid int32")
+			}
+		case "int64":
+			if v < math.MinInt64 || v > math.MaxInt64 || v != math.Trunc(v) {
+				fail("value is not a valid int64")
+			}
+		case "float":
+			if math.Abs(v) > math.MaxFloat32 {
+				fail("value is not a valid float")
+			}
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateArray(v []interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		fail("array must have >= %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		fail("array must have <= %d items", *s.MaxItems)
+	}
+	if s.UniqueItems {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if reflect.DeepEqual(v[i], v[j]) {
+					fail("array items must be unique, items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range v {
+			errs = append(errs, s.Items.ValidateValue(item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateObject(v map[string]interface{}, pointer string) []*ValidationError {
+	var errs []*ValidationError
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			fail("required property %s is missing", name)
+		}
+	}
+	if s.MinProperties != nil && len(v) < *s.MinProperties {
+		fail("object must have >= %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(v) > *s.MaxProperties {
+		fail("object must have <= %d properties", *s.MaxProperties)
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := pointer + "/" + escapePointer(name)
+		if prop, ok := s.Properties[name]; ok {
+			errs = append(errs, prop.ValidateValue(v[name], child)...)
+			continue
+		}
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, &ValidationError{Pointer: child, Message: "additional property is not allowed"})
+			}
+		case *Schema:
+			errs = append(errs, additional.ValidateValue(v[name], child)...)
+		case map[string]interface{}:
+			//AdditionalProperties decoded from a document, read it as a schema
+			if data, err := json.Marshal(additional); err == nil {
+				schema := &Schema{}
+				if json.Unmarshal(data, schema) == nil {
+					errs = append(errs, schema.ValidateValue(v[name], child)...)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+//countValid returns the number of schemas the value is valid against
+func countValid(schemas []*Schema, value interface{}, pointer string) int {
+	count := 0
+	for _, s := range schemas {
+		if len(s.ValidateValue(value, pointer)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+//isType checks if the value is of the schema type
+func isType(value interface{}, t string) bool {
+	switch t {
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeArray:
+		_, ok := value.([]interface{})
+		return ok
+	case TypeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case TypeNull:
+		return value == nil
+	case TypeNumber:
+		_, ok := toFloat(value)
+		return ok
+	case TypeInteger:
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	}
+	return true
+}
+
+//typeOf returns the schema type name of the value
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return TypeNull
+	case string:
+		return TypeString
+	case bool:
+		return TypeBoolean
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	}
+	if _, ok := toFloat(value); ok {
+		return TypeNumber
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+//toFloat converts the numeric values to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+//inEnum checks if the value is one of the enum values, numbers are compared by their value
+func inEnum(value interface{}, enum []interface{}) bool {
+	n, isNum := toFloat(value)
+	for _, e := range enum {
+		if isNum {
+			if m, ok := toFloat(e); ok && m == n {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(value, e) {
+			return true
+		}
+	}
+	return false
+}
+
+//validFormat checks the string formats, unknown formats are considered valid
+func validFormat(format, v string) bool {
+	var err error
+	switch format {
+	case "date":
+		_, err = time.Parse("2006-01-02", v)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", v)
+	case "email":
+		var addr *mail.Address
+		if addr, err = mail.ParseAddress(v); err == nil && addr.Address != v {
+			return false
+		}
+	case "uuid":
+		return uuidExp.MatchString(v)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(v); err == nil && !u.IsAbs() {
+			return false
+		}
+	case "uri-reference":
+		_, err = url.Parse(v)
+	case "ipv4":
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() != nil && strings.Contains(v, ".")
+	case "ipv6":
+		ip := net.ParseIP(v)
+		return ip != nil && strings.Contains(v, ":")
+	case "byte":
+		_, err = base64.StdEncoding.DecodeString(v)
+	}
+	return err == nil
+}
+
+//compilePattern compiles the pattern and caches it for the subsequent validations
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if exp, ok := patterns.Load(pattern); ok {
+		return exp.(*regexp.Regexp), nil
+	}
+	exp, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, exp)
+	return exp, nil
+}
+
+//escapePointer escapes a JSON pointer reference token as per RFC 6901
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
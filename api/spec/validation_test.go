@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchema_ValidateValue(t *testing.T) {
+	min, max := 1.0, 10.0
+	minLen, maxLen := 2, 4
+	pattern := "^[a-z]+$"
+	uuid, int32Format := "uuid", "int32"
+	schema := &Schema{
+		Type:     TypeObject,
+		Required: []string{"name", "count"},
+		Properties: map[string]*Schema{
+			"name":  {Type: TypeString, MinLength: &minLen, MaxLength: &maxLen, Pattern: &pattern},
+			"count": {Type: TypeInteger, Minimum: &min, Maximum: &max, Format: &int32Format},
+			"id":    {Type: TypeString, Format: &uuid},
+			"kind":  {Type: TypeString, Enum: []interface{}{"cat", "dog"}},
+			"tags":  {Type: TypeArray, UniqueItems: true, Items: &Schema{Type: TypeString}},
+		},
+		AdditionalProperties: false,
+	}
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{
+			name:  "Valid",
+			value: `{"name": "abc", "count": 5, "id": "123e4567-e89b-12d3-a456-426614174000", "kind": "cat", "tags": ["a", "b"]}`,
+		},
+		{
+			name:  "Required",
+			value: `{"name": "abc"}`,
+			want:  []string{"/body: required property count is missing"},
+		},
+		{
+			name:  "Constraints",
+			value: `{"name": "ABCDE", "count": 11.5, "id": "abc", "kind": "cow", "tags": ["a", "a", 1], "extra": true}`,
+			want: []string{
+				"/body/count: expected type integer but found number",
+				"/body/extra: additional property is not allowed",
+				"/body/id: value is not a valid uuid",
+				"/body/kind: value must be one of [cat dog]",
+				"/body/name: length must be <= 4",
+				"/body/name: value must match the pattern ^[a-z]+$",
+				"/body/tags: array items must be unique, items 0 and 1 are equal",
+				"/body/tags/2: expected type string but found number",
+			},
+		},
+		{
+			name:  "Type",
+			value: `[]`,
+			want:  []string{"/body: expected type object but found array"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tt.value), &v); err != nil {
+				t.Fatal(err)
+			}
+			errs := schema.ValidateValue(v, "/body")
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateValueComposition(t *testing.T) {
+	schema := &Schema{
+		OneOf: []*Schema{{Type: TypeString}, {Type: TypeInteger}},
+		Not:   &Schema{Type: TypeString, Enum: []interface{}{"forbidden"}},
+	}
+	if errs := schema.ValidateValue(float64(1), "/v"); len(errs) != 0 {
+		t.Errorf("ValidateValue() = %v, want no errors", errs)
+	}
+	if errs := schema.ValidateValue(true, "/v"); len(errs) != 1 {
+		t.Errorf("ValidateValue() = %v, want oneOf error", errs)
+	}
+	if errs := schema.ValidateValue("forbidden", "/v"); len(errs) != 1 {
+		t.Errorf("ValidateValue() = %v, want not error", errs)
+	}
+	if errs := (&Schema{Type: TypeString, Nullable: true}).ValidateValue(nil, "/v"); len(errs) != 0 {
+		t.Errorf("ValidateValue() = %v, want no errors for nullable", errs)
+	}
+}
@@ -78,6 +78,24 @@ func BenchmarkFindRoutePathParam(b *testing.B) {
 	}
 }
 
+// BenchmarkFindRouteRootParam: Path Param at Root Context Test
+func BenchmarkFindRouteRootParam(b *testing.B) {
+	var router = NewRouter()
+	router.Get("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]byte("hello from turbo"))
+	})
+	router.Get("/:tenant/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]byte("hello from turbo"))
+	})
+	testUrl, _ := url.Parse("/acme/v1/health")
+	req := &http.Request{
+		URL: testUrl,
+	}
+	for i := 0; i < b.N; i++ {
+		router.findRoute(req)
+	}
+}
+
 func BenchmarkRouter_ServeHTTPStatic(b *testing.B) {
 	var router = NewRouter()
 	router.Get("/api/fooTest", func(w http.ResponseWriter, r *http.Request) {
@@ -107,3 +125,18 @@ func BenchmarkRouter_ServeHTTPParams(b *testing.B) {
 		router.ServeHTTP(w, r)
 	}
 }
+
+func BenchmarkRouter_ServeHTTPRootParams(b *testing.B) {
+	var router = NewRouter()
+	router.Get("/{tenant}/fooTest/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Benchmarking!"))
+	})
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest(GET, "/acme/fooTest/123", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, r)
+	}
+}
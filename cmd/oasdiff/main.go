@@ -0,0 +1,51 @@
+//oasdiff compares two versions of an OAS document and exits with a non zero status when any change is breaking.
+//
+//Usage:
+//
+//	oasdiff [-json] old.yaml new.yaml
+//
+//Documents split across files are bundled before the comparison.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: oasdiff [-json] <old spec> <new spec>")
+		os.Exit(2)
+	}
+	old, err := spec.Bundle(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	new, err := spec.Bundle(flag.Arg(1))
+	if err != nil {
+		fail(err)
+	}
+	report := spec.Diff(old, new)
+	if *asJSON {
+		data, err := report.JSON()
+		if err != nil {
+			fail(err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report.Text())
+	}
+	if report.HasBreaking() {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "oasdiff: %v\n", err)
+	os.Exit(2)
+}
@@ -0,0 +1,59 @@
+//oasgen generates the Go models, the server stubs and the client of an OAS document.
+//
+//Usage:
+//
+//	oasgen -spec api/openapi.yaml -package api [-server=false] [-client] -o api.gen.go
+//
+//It is meant to be run by go generate
+//
+//	//go:generate go run go.nandlabs.io/turbo/cmd/oasgen -spec openapi.yaml -package api -o api.gen.go
+//
+//Documents split across files are bundled before the generation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"go.nandlabs.io/turbo/api/codegen"
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func main() {
+	specFile := flag.String("spec", "", "path of the OAS document")
+	pkg := flag.String("package", "", "package name of the generated file, defaults to $GOPACKAGE")
+	out := flag.String("o", "", "output file, defaults to the standard output")
+	server := flag.Bool("server", true, "generate the Server interface and the Register function")
+	client := flag.Bool("client", false, "generate the Client")
+	flag.Parse()
+	if *pkg == "" {
+		*pkg = os.Getenv("GOPACKAGE")
+	}
+	if *specFile == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	oas, err := spec.Bundle(*specFile)
+	if err != nil {
+		fail(err)
+	}
+	src, err := codegen.Generate(oas, codegen.Options{Package: *pkg, Server: *server, Client: *client})
+	if err != nil {
+		fail(err)
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = ioutil.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "oasgen: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,38 @@
+//oaslint checks the structure of OAS documents and exits with a non zero status when any violation is found.
+//
+//Usage:
+//
+//	oaslint api/openapi.yaml [more.yaml ...]
+//
+//Documents split across files are bundled before the check.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: oaslint <spec file>...")
+		os.Exit(2)
+	}
+	failed := false
+	for _, path := range os.Args[1:] {
+		oas, err := spec.Bundle(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		for _, violation := range oas.Validate() {
+			fmt.Printf("%s#%s\n", path, violation)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
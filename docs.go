@@ -0,0 +1,195 @@
+package turbo
+
+import (
+	"net/http"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//Paths of the API documentation endpoints
+const (
+	OpenAPIJSONPath = "/openapi.json"
+	OpenAPIYAMLPath = "/openapi.yaml"
+	DocsPath        = "/docs"
+)
+
+//ServeOpenAPI mounts the OAS document on the router at GET /openapi.json and GET /openapi.yaml along with an HTML
+//page at GET /docs rendering the document. The page is self-contained, it does not load any external assets.
+//The document is serialized once, changes made to the OAS after the call are not served.
+func (router *Router) ServeOpenAPI(oas *spec.OAS) error {
+	jsonDoc, err := oas.ToJSON()
+	if err != nil {
+		return err
+	}
+	yamlDoc, err := oas.ToYAML()
+	if err != nil {
+		return err
+	}
+	router.Get(OpenAPIJSONPath, staticContent("application/json; charset=utf-8", jsonDoc))
+	router.Get(OpenAPIYAMLPath, staticContent("application/yaml; charset=utf-8", yamlDoc))
+	router.Get(DocsPath, staticContent("text/html; charset=utf-8", []byte(docsPage)))
+	return nil
+}
+
+//staticContent creates a handler that writes the content with the content type
+func staticContent(contentType string, content []byte) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(content); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+//docsPage renders the document served at OpenAPIJSONPath
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>API Documentation</title>
+<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;margin:0;color:#24292e;background:#f6f8fa}
+header{background:#24292e;color:#fff;padding:16px 32px}
+header h1{margin:0;font-size:24px}
+header .version{font-size:13px;background:#586069;border-radius:8px;padding:2px 8px;margin-left:8px}
+main{max-width:1100px;margin:0 auto;padding:16px 32px}
+a{color:#0366d6}
+.links{font-size:13px;margin-top:8px}
+.links a{color:#c8e1ff;margin-right:16px}
+.tag{margin-top:24px;font-size:20px;border-bottom:1px solid #e1e4e8;padding-bottom:4px}
+details.op{background:#fff;border:1px solid #e1e4e8;border-radius:6px;margin:8px 0}
+details.op summary{cursor:pointer;padding:8px 12px;font-family:monospace;font-size:15px;list-style:none}
+details.op .body{padding:0 16px 12px}
+.method{display:inline-block;min-width:64px;text-align:center;border-radius:4px;color:#fff;font-weight:bold;padding:2px 6px;margin-right:8px}
+.GET{background:#2188ff}.POST{background:#28a745}.PUT{background:#f66a0a}.DELETE{background:#d73a49}.PATCH{background:#6f42c1}
+.HEAD,.OPTIONS,.TRACE{background:#586069}
+.deprecated{text-decoration:line-through;opacity:.6}
+.summary{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",sans-serif;color:#586069;margin-left:8px}
+table{border-collapse:collapse;width:100%;font-size:14px;margin:8px 0}
+th,td{border:1px solid #e1e4e8;padding:4px 8px;text-align:left;vertical-align:top}
+th{background:#f6f8fa}
+pre{background:#f6f8fa;border:1px solid #e1e4e8;border-radius:4px;padding:8px;overflow:auto;font-size:13px}
+h4{margin:12px 0 4px}
+.required{color:#d73a49}
+.error{color:#d73a49}
+</style>
+</head>
+<body>
+<header><h1 id="title">API Documentation</h1><div class="links"><a href="openapi.json">openapi.json</a><a href="openapi.yaml">openapi.yaml</a></div></header>
+<main id="content"><p>Loading...</p></main>
+<script>
+(function () {
+  "use strict";
+  var methods = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) { node.setAttribute(k, attrs[k]); });
+    (children || []).forEach(function (c) {
+      node.appendChild(typeof c === "string" ? document.createTextNode(c) : c);
+    });
+    return node;
+  }
+  function text(tag, value, attrs) { return el(tag, attrs, [value == null ? "" : String(value)]); }
+  function schemaText(schema) {
+    if (!schema) { return ""; }
+    if (schema.$ref) { return schema.$ref.replace("#/components/schemas/", ""); }
+    if (schema.type === "array") { return "array of " + schemaText(schema.items); }
+    return (schema.type || "any") + (schema.format ? " (" + schema.format + ")" : "");
+  }
+  function content(title, c) {
+    var nodes = [];
+    Object.keys(c || {}).forEach(function (mediaType) {
+      nodes.push(text("h4", title + " " + mediaType));
+      nodes.push(text("pre", JSON.stringify(c[mediaType].schema || {}, null, 2)));
+    });
+    return nodes;
+  }
+  function parameters(params) {
+    if (!params || !params.length) { return []; }
+    var rows = params.map(function (p) {
+      if (p.$ref) { return el("tr", {}, [el("td", {colspan: "4"}, [p.$ref])]); }
+      var name = text("td", p.name);
+      if (p.required) { name.appendChild(text("span", " *", {"class": "required"})); }
+      return el("tr", {}, [name, text("td", p.in), text("td", schemaText(p.schema)), text("td", p.description)]);
+    });
+    return [text("h4", "Parameters"), el("table", {}, [
+      el("tr", {}, [text("th", "Name"), text("th", "In"), text("th", "Type"), text("th", "Description")])
+    ].concat(rows))];
+  }
+  function operation(path, method, op, common) {
+    var upper = method.toUpperCase();
+    var summary = el("summary", {}, [text("span", upper, {"class": "method " + upper}),
+      text("span", path, op.deprecated ? {"class": "deprecated"} : {}), text("span", op.summary, {"class": "summary"})]);
+    var body = el("div", {"class": "body"});
+    if (op.description) { body.appendChild(text("p", op.description)); }
+    if (op.operationId) { body.appendChild(text("p", "operationId: " + op.operationId)); }
+    parameters((common || []).concat(op.parameters || [])).forEach(function (n) { body.appendChild(n); });
+    if (op.requestBody) {
+      content("Request Body" + (op.requestBody.required ? " (required)" : ""), op.requestBody.content).forEach(function (n) { body.appendChild(n); });
+    }
+    var responses = op.responses || {};
+    if (Object.keys(responses).length) {
+      body.appendChild(text("h4", "Responses"));
+      body.appendChild(el("table", {}, [el("tr", {}, [text("th", "Status"), text("th", "Description")])].concat(
+        Object.keys(responses).sort().map(function (code) {
+          var r = responses[code] || {};
+          return el("tr", {}, [text("td", code), text("td", r.$ref || r.description)]);
+        }))));
+      Object.keys(responses).sort().forEach(function (code) {
+        content("Response " + code, (responses[code] || {}).content).forEach(function (n) { body.appendChild(n); });
+      });
+    }
+    return el("details", {"class": "op"}, [summary, body]);
+  }
+  function render(oas) {
+    var info = oas.info || {};
+    document.title = info.title || document.title;
+    var title = document.getElementById("title");
+    title.textContent = info.title || "API Documentation";
+    title.appendChild(text("span", info.version, {"class": "version"}));
+    var main = document.getElementById("content");
+    main.textContent = "";
+    if (info.description) { main.appendChild(text("p", info.description)); }
+    var groups = {}, order = [];
+    Object.keys(oas.paths || {}).sort().forEach(function (path) {
+      var item = oas.paths[path] || {};
+      methods.forEach(function (method) {
+        var op = item[method];
+        if (!op) { return; }
+        var tag = (op.tags && op.tags[0]) || "default";
+        if (!groups[tag]) { groups[tag] = []; order.push(tag); }
+        groups[tag].push(operation(path, method, op, item.parameters));
+      });
+    });
+    order.forEach(function (tag) {
+      main.appendChild(text("h2", tag, {"class": "tag"}));
+      groups[tag].forEach(function (n) { main.appendChild(n); });
+    });
+    var schemas = (oas.components || {}).schemas || {};
+    if (Object.keys(schemas).length) {
+      main.appendChild(text("h2", "Schemas", {"class": "tag"}));
+      Object.keys(schemas).sort().forEach(function (name) {
+        main.appendChild(el("details", {"class": "op"}, [text("summary", name),
+          el("div", {"class": "body"}, [text("pre", JSON.stringify(schemas[name], null, 2))])]));
+      });
+    }
+  }
+  var xhr = new XMLHttpRequest();
+  xhr.open("GET", "openapi.json");
+  xhr.onload = function () {
+    try {
+      render(JSON.parse(xhr.responseText));
+    } catch (e) {
+      var main = document.getElementById("content");
+      main.textContent = "";
+      main.appendChild(text("p", "Unable to render the document: " + e.message, {"class": "error"}));
+    }
+  };
+  xhr.send();
+})();
+</script>
+</body>
+</html>
+`
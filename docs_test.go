@@ -0,0 +1,43 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func TestRouter_ServeOpenAPI(t *testing.T) {
+	router := NewRouter()
+	router.Get("/api/v1/pets/{id}", dummyHandler)
+	if err := router.ServeOpenAPI(router.OpenAPI(spec.Info{Title: "Pets", Version: "1.0.0"})); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{OpenAPIJSONPath, "application/json", `"/api/v1/pets/{id}": {`},
+		{OpenAPIYAMLPath, "application/yaml", "  /api/v1/pets/{id}:\n"},
+		{DocsPath, "text/html", "<script>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(GET, tt.path, nil)
+			router.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("ServeHTTP() got = %v, want = %v", w.Code, http.StatusOK)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("ServeHTTP() content type = %v, want = %v", ct, tt.contentType)
+			}
+			if !strings.Contains(w.Body.String(), tt.contains) {
+				t.Errorf("ServeHTTP() body = %v, want to contain %v", w.Body.String(), tt.contains)
+			}
+		})
+	}
+}
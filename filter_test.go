@@ -3,6 +3,7 @@ package turbo
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -118,3 +119,90 @@ func TestAuthenticatorFilter(t *testing.T) {
 		t.Error("Auth Filter not working")
 	}
 }
+
+//traceFilter adds its name to the X-Trace header of the response
+func traceFilter(name string) FilterFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRouter_Use(t *testing.T) {
+	router := NewRouter()
+	router.Use(traceFilter("global1"), traceFilter("global2"))
+	router.Get("/api/foo", testHandler).AddFilter(traceFilter("route")).
+		AddAuthenticator(headerAuthenticator("token"))
+	router.Group("/api/group", traceFilter("group")).Get("/bar", testHandler).AddFilter(traceFilter("route"))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  bool
+		status int
+		trace  string
+	}{
+		{
+			name:   "route",
+			method: GET,
+			path:   "/api/foo",
+			token:  true,
+			status: http.StatusOK,
+			trace:  "global1,global2,route",
+		},
+		{
+			name:   "group",
+			method: GET,
+			path:   "/api/group/bar",
+			status: http.StatusOK,
+			trace:  "global1,global2,group,route",
+		},
+		{
+			name:   "authenticator after the router filters",
+			method: GET,
+			path:   "/api/foo",
+			status: http.StatusForbidden,
+			trace:  "global1,global2",
+		},
+		{
+			name:   "not found",
+			method: GET,
+			path:   "/api/missing",
+			status: http.StatusNotFound,
+			trace:  "global1,global2",
+		},
+		{
+			name:   "method not allowed",
+			method: POST,
+			path:   "/api/foo",
+			status: http.StatusMethodNotAllowed,
+			trace:  "global1,global2",
+		},
+		{
+			name:   "redirect",
+			method: GET,
+			path:   "/api//foo",
+			status: http.StatusMovedPermanently,
+			trace:  "global1,global2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token {
+				r.Header.Set("token", "value")
+			}
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if trace := strings.Join(w.Header().Values("X-Trace"), ","); trace != tt.trace {
+				t.Errorf("trace = %v, want %v", trace, tt.trace)
+			}
+		})
+	}
+}
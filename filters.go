@@ -13,21 +13,42 @@ type FilterFunc func(http.Handler) http.Handler
 //if f1, f2, f3, finalHandler handlers are added to the filter chain then the order of execution remains
 //f1 -> f2 -> f3 -> finalHandler
 func (route *Route) AddFilter(filter ...FilterFunc) *Route {
-	newFilters := make([]FilterFunc, 0, len(route.filters)+len(filter))
-	newFilters = append(newFilters, route.filters...)
+	return route.update(func() {
+		newFilters := make([]FilterFunc, 0, len(route.filters)+len(filter))
+		newFilters = append(newFilters, route.filters...)
+		newFilters = append(newFilters, filter...)
+		route.filters = newFilters
+	})
+}
+
+//Use adds filters wrapping the whole dispatch of the router, they are executed for every request including the ones
+//answered with 404 Not Found, 405 Method Not Allowed or a redirect. The order of execution is
+//router filters -> authenticator -> group filters -> route filters -> finalHandler
+func (router *Router) Use(filter ...FilterFunc) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	newFilters := make([]FilterFunc, 0, len(router.filters)+len(filter))
+	newFilters = append(newFilters, router.filters...)
 	newFilters = append(newFilters, filter...)
-	route.filters = newFilters
-	return route
+	router.filters = newFilters
+	var dispatcher http.Handler = http.HandlerFunc(router.dispatch)
+	for i := range newFilters {
+		dispatcher = newFilters[len(newFilters)-1-i](dispatcher)
+	}
+	router.dispatcher = dispatcher
+	return router
 }
 
 //AddAuthenticator Adding the authenticator filter to the route
 func (route *Route) AddAuthenticator(auth auth.Authenticator) *Route {
-	route.authFilter = auth
-	return route
+	return route.update(func() {
+		route.authFilter = auth
+	})
 }
 
 //SetLogger Sets the custom logger is required at the route level
 func (route *Route) SetLogger(logger *l3.BaseLogger) *Route {
-	route.logger = logger
-	return route
+	return route.update(func() {
+		route.logger = logger
+	})
 }
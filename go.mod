@@ -5,4 +5,5 @@ go 1.14
 require (
 	go.nandlabs.io/commons v0.0.1
 	go.nandlabs.io/l3 v0.0.1
+	gopkg.in/yaml.v3 v3.0.1
 )
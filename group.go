@@ -0,0 +1,129 @@
+package turbo
+
+import (
+	"net/http"
+	"strings"
+
+	"go.nandlabs.io/turbo/auth"
+)
+
+//Group registers routes under a common path prefix. The filters and the authenticator of the group apply to every
+//route registered through the group or its nested groups, including the filters added after the route.
+type Group struct {
+	router *Router
+	parent *Group
+	//prefix is the full path prefix of the group including the prefixes of the parents
+	prefix string
+	//filters of the group, these are executed before the filters of the nested groups and the route
+	filters []FilterFunc
+	//authFilter of the group, the authenticator of a nested group or of the route takes precedence
+	authFilter auth.Authenticator
+}
+
+//Group creates a group of routes under the path prefix with the filters
+func (router *Router) Group(prefix string, filters ...FilterFunc) *Group {
+	group := &Group{router: router, prefix: groupPrefix(prefix)}
+	return group.AddFilter(filters...)
+}
+
+//Group creates a group nested in this group, the prefix is appended to the prefix of this group
+func (group *Group) Group(prefix string, filters ...FilterFunc) *Group {
+	nested := &Group{router: group.router, parent: group, prefix: group.prefix + groupPrefix(prefix)}
+	return nested.AddFilter(filters...)
+}
+
+//groupPrefix normalises the prefix to start with a / and not end with one
+func groupPrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), PathSeparator)
+	if prefix == "" {
+		return ""
+	}
+	return PathSeparator + prefix
+}
+
+//Prefix returns the full path prefix of the group
+func (group *Group) Prefix() string {
+	return group.prefix
+}
+
+//AddFilter adds the filters to the group, they are executed in the order they are added
+func (group *Group) AddFilter(filter ...FilterFunc) *Group {
+	group.router.lock.Lock()
+	defer group.router.lock.Unlock()
+	newFilters := make([]FilterFunc, 0, len(group.filters)+len(filter))
+	newFilters = append(newFilters, group.filters...)
+	newFilters = append(newFilters, filter...)
+	group.filters = newFilters
+	return group
+}
+
+//AddAuthenticator sets the authenticator of the group
+func (group *Group) AddAuthenticator(auth auth.Authenticator) *Group {
+	group.router.lock.Lock()
+	defer group.router.lock.Unlock()
+	group.authFilter = auth
+	return group
+}
+
+//Add a turbo handler for one or more HTTP methods at the path relative to the prefix of the group.
+func (group *Group) Add(path string, f func(w http.ResponseWriter, r *http.Request), methods ...string) *Route {
+	path = strings.TrimSpace(path)
+	if path == PathSeparator {
+		path = ""
+	}
+	if path != "" && !strings.HasPrefix(path, PathSeparator) {
+		path = PathSeparator + path
+	}
+	fullPath := group.prefix + path
+	if fullPath == "" {
+		fullPath = PathSeparator
+	}
+	group.router.lock.Lock()
+	defer group.router.lock.Unlock()
+	return group.router.add(fullPath, f, false, group, methods)
+}
+
+//Get to Add a turbo handler for GET method relative to the prefix of the group
+func (group *Group) Get(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return group.Add(path, f, GET)
+}
+
+//Post to Add a turbo handler for POST method relative to the prefix of the group
+func (group *Group) Post(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return group.Add(path, f, POST)
+}
+
+//Put to Add a turbo handler for PUT method relative to the prefix of the group
+func (group *Group) Put(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return group.Add(path, f, PUT)
+}
+
+//Delete to Add a turbo handler for DELETE method relative to the prefix of the group
+func (group *Group) Delete(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return group.Add(path, f, DELETE)
+}
+
+//Any to Add a turbo handler for every HTTP method relative to the prefix of the group
+func (group *Group) Any(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return group.Add(path, f, anyMethod)
+}
+
+//apply wraps the handler with the filters of the group and its parents, the outermost group filters run first
+func (group *Group) apply(handler http.Handler) http.Handler {
+	for g := group; g != nil; g = g.parent {
+		for i := range g.filters {
+			handler = g.filters[len(g.filters)-1-i](handler)
+		}
+	}
+	return handler
+}
+
+//authenticator returns the authenticator of the group or of its nearest parent that has one
+func (group *Group) authenticator() auth.Authenticator {
+	for g := group; g != nil; g = g.parent {
+		if g.authFilter != nil {
+			return g.authFilter
+		}
+	}
+	return nil
+}
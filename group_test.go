@@ -0,0 +1,108 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//headerAuthenticator allows the requests having the header
+type headerAuthenticator string
+
+func (h headerAuthenticator) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(string(h)) == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestRouter_Group(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api/v1/", filterFunction("api/"))
+	api.AddAuthenticator(headerAuthenticator("token"))
+	api.Get("/users", testHandler)
+	api.Get("", testHandler)
+	admin := api.Group("admin", filterFunction("admin/"))
+	admin.Get("/stats", testHandler).AddFilter(filterFunction("route/"))
+	public := router.Group("/public")
+	public.Get("/status", testHandler)
+	//filters added after the routes apply too
+	admin.AddFilter(filterFunction("late/"))
+	override := api.Get("/open", testHandler)
+	override.AddAuthenticator(headerAuthenticator("key"))
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		status int
+		body   string
+	}{
+		{
+			name:   "group route",
+			path:   "/api/v1/users",
+			header: "token",
+			status: http.StatusOK,
+			body:   "api/testHandler",
+		},
+		{
+			name:   "group prefix",
+			path:   "/api/v1",
+			header: "token",
+			status: http.StatusOK,
+			body:   "api/testHandler",
+		},
+		{
+			name:   "group authenticator",
+			path:   "/api/v1/users",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "nested group",
+			path:   "/api/v1/admin/stats",
+			header: "token",
+			status: http.StatusOK,
+			body:   "api/admin/late/route/testHandler",
+		},
+		{
+			name:   "nested group inherits the authenticator",
+			path:   "/api/v1/admin/stats",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "route authenticator overrides the group",
+			path:   "/api/v1/open",
+			header: "key",
+			status: http.StatusOK,
+			body:   "api/testHandler",
+		},
+		{
+			name:   "group without authenticator",
+			path:   "/public/status",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(GET, tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, "value")
+			}
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+	if admin.Prefix() != "/api/v1/admin" {
+		t.Errorf("Prefix() = %v, want /api/v1/admin", admin.Prefix())
+	}
+}
@@ -16,6 +16,7 @@ const (
 	OPTIONS       = "OPTIONS"
 	TRACE         = "TRACE"
 	PATCH         = "PATCH"
+	CONNECT       = "CONNECT"
 )
 
 var Methods = map[string]string{
@@ -27,6 +28,7 @@ var Methods = map[string]string{
 	OPTIONS: OPTIONS,
 	TRACE:   TRACE,
 	PATCH:   PATCH,
+	CONNECT: CONNECT,
 }
 
 //refinePath Borrowed from the golang's net/turbo package
@@ -71,3 +73,21 @@ func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
 func methodNotAllowedHandler() http.Handler {
 	return http.HandlerFunc(methodNotAllowed)
 }
+
+//queryParamMissing responds with 400 when a required query param is not present in the request
+func queryParamMissing(w http.ResponseWriter, r *http.Request, name string) {
+	w.WriteHeader(http.StatusBadRequest)
+	_, err := w.Write([]byte("Required Query Param : " + name + " not present for Endpoint : " + r.URL.Path + "\n"))
+	if err != nil {
+		return
+	}
+}
+
+//variantNotMatched responds with the status when the request does not match any variant of the handler of the route
+func variantNotMatched(w http.ResponseWriter, r *http.Request, status int) {
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(http.StatusText(status) + " : " + r.Method + " " + r.URL.Path + "\n"))
+	if err != nil {
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package turbo
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+)
+
+//host is a router serving the requests for the hosts matching a pattern
+type host struct {
+	//pattern as it was registered with the scheme and the static labels lower-cased
+	pattern string
+	//scheme to match if any
+	scheme string
+	//labels of the host name matched as the segments of a route
+	labels []*Route
+	//port to match if any
+	port   string
+	router *Router
+	//vars is the number of variables and wildcards among the labels, the hosts with less of them are matched first
+	vars int
+}
+
+//Host returns the router serving the requests whose host matches the pattern, the requests not matching any host are
+//served by this router. The pattern is a host name optionally preceded by the scheme and followed by the port as in
+//https://api.example.com:8443. The labels of the host name can be variables such as {tenant}.example.com, their
+//values are added to the path params of the request, or a * matching any label. The hosts without variables are
+//matched first, then in the order they are added. Host returns the same router when called again with the pattern.
+//The host of the request is matched in lower case, so the values of the variables are lower-cased.
+//The router of the host starts with the methods, the trailing slash, the redirect, the HEAD and OPTIONS and the
+//response validation settings of this router, the changes made afterwards apply to either router alone.
+func (router *Router) Host(pattern string) *Router {
+	h := newHost(pattern)
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	for _, existing := range router.hosts {
+		if existing.pattern == h.pattern {
+			return existing.router
+		}
+	}
+	logger.InfoF("Registering New Host: %s", h.pattern)
+	h.router = NewRouter()
+	//the methods are copied on write so they can be shared
+	h.router.methods = router.methods
+	h.router.trailingSlash = router.trailingSlash
+	h.router.redirectCode = router.redirectCode
+	h.router.autoHead = router.autoHead
+	h.router.autoOptions = router.autoOptions
+	h.router.allowHeader = router.allowHeader
+	h.router.responseValidation = router.responseValidation
+	hosts := make([]*host, 0, len(router.hosts)+1)
+	hosts = append(hosts, router.hosts...)
+	hosts = append(hosts, h)
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return hosts[i].vars < hosts[j].vars
+	})
+	router.hosts = hosts
+	return h.router
+}
+
+//newHost parses the pattern of the host, the scheme and the static labels are lower-cased while the names and the
+//constraints of the variables are kept as written
+func newHost(pattern string) *host {
+	h := &host{}
+	name := strings.TrimSpace(pattern)
+	if idx := strings.Index(name, "://"); idx >= 0 {
+		h.scheme = strings.ToLower(name[:idx])
+		name = name[idx+3:]
+		if h.scheme != "http" && h.scheme != "https" {
+			panic(fmt.Sprintf("invalid scheme of the host %s", pattern))
+		}
+	}
+	labels := splitTemplate(name, textutils.ColonChar)
+	switch len(labels) {
+	case 1:
+	case 2:
+		name, h.port = labels[0], labels[1]
+		if h.port == textutils.EmptyStr {
+			panic(fmt.Sprintf("invalid port of the host %s", pattern))
+		}
+	default:
+		panic(fmt.Sprintf("invalid host %s", pattern))
+	}
+	labels = splitTemplate(name, '.')
+	for i, label := range labels {
+		route := newSegmentRoute(label, false)
+		if label == textutils.EmptyStr || route.isCatchAll {
+			panic(fmt.Sprintf("invalid host %s", pattern))
+		}
+		if route.isPathVar || route.isWildcard {
+			h.vars++
+		} else {
+			route.path = strings.ToLower(route.path)
+			labels[i] = route.path
+		}
+		h.labels = append(h.labels, route)
+	}
+	//the pattern identifies the host regardless of the case of its scheme and static labels
+	h.pattern = strings.Join(labels, ".")
+	if h.scheme != textutils.EmptyStr {
+		h.pattern = h.scheme + "://" + h.pattern
+	}
+	if h.port != textutils.EmptyStr {
+		h.pattern += string(textutils.ColonChar) + h.port
+	}
+	return h
+}
+
+//match checks if the request is for the host and returns the values of the variables of the host
+func (h *host) match(r *http.Request) ([]Param, bool) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if r.URL.Scheme != textutils.EmptyStr {
+		scheme = strings.ToLower(r.URL.Scheme)
+	}
+	if h.scheme != textutils.EmptyStr && h.scheme != scheme {
+		return nil, false
+	}
+	name, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		name = r.Host
+		port = "80"
+		if scheme == "https" {
+			port = "443"
+		}
+	}
+	if h.port != textutils.EmptyStr && h.port != port {
+		return nil, false
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+	var params []Param
+	for i, label := range h.labels {
+		if !label.matches(labels[i]) {
+			return nil, false
+		}
+		if label.isPathVar {
+			params = append(params, Param{key: label.path, value: labels[i]})
+		}
+	}
+	return params, true
+}
+
+//findHost returns the router of the host matching the request with the request to pass to it, it is called with the
+//lock held
+func (router *Router) findHost(r *http.Request) (*Router, *http.Request) {
+	for _, h := range router.hosts {
+		if params, ok := h.match(r); ok {
+			return h.router, withParams(r, params)
+		}
+	}
+	return nil, r
+}
@@ -0,0 +1,223 @@
+package turbo
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_Host(t *testing.T) {
+	router := NewRouter()
+	router.Get("/status", methodHandler("default"))
+	router.Host("api.example.com").Get("/status", methodHandler("api"))
+	tenants := router.Host("{tenant}.example.com")
+	tenants.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ := tenants.GetPathParams("tenant", r)
+		id, _ := tenants.GetPathParams("id", r)
+		w.Write([]byte(tenant + "/" + id))
+	})
+	router.Host("https://admin.example.com").Get("/status", methodHandler("admin"))
+	router.Host("internal.example.com:8443").Get("/status", methodHandler("internal"))
+	router.Host("*.{region:eu|us}.example.com").Get("/status", paramHandler(router, "region"))
+
+	tests := []struct {
+		name   string
+		host   string
+		tls    bool
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "static host",
+			host:   "api.example.com",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "api:GET",
+		},
+		{
+			name:   "host with port and case",
+			host:   "API.example.com:8080",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "api:GET",
+		},
+		{
+			name:   "host variable",
+			host:   "acme.example.com",
+			path:   "/orders/42",
+			status: http.StatusOK,
+			body:   "acme/42",
+		},
+		{
+			name:   "host without the route",
+			host:   "acme.example.com",
+			path:   "/status",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "default router",
+			host:   "example.com",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "default:GET",
+		},
+		{
+			name:   "scheme",
+			host:   "admin.example.com",
+			tls:    true,
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "admin:GET",
+		},
+		{
+			name:   "scheme not matching falls back to the host variable",
+			host:   "admin.example.com",
+			path:   "/status",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "port",
+			host:   "internal.example.com:8443",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "internal:GET",
+		},
+		{
+			name:   "port not matching",
+			host:   "internal.example.com",
+			path:   "/status",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "wildcard and constrained labels",
+			host:   "shop.eu.example.com",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "region=eu",
+		},
+		{
+			name:   "constrained label not matching",
+			host:   "shop.asia.example.com",
+			path:   "/status",
+			status: http.StatusOK,
+			body:   "default:GET",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(GET, tt.path, nil)
+			r.Host = tt.host
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+
+	if router.Host("API.example.com") != router.Host("api.example.com") {
+		t.Errorf("Host() returned a new router for the same pattern")
+	}
+}
+
+func TestRouter_HostCase(t *testing.T) {
+	router := NewRouter()
+	api := router.Host("HTTPS://API.Example.com")
+	api.Get("/status", methodHandler("api"))
+	if router.Host("https://api.example.COM") != api {
+		t.Errorf("Host() did not return the router of the pattern in another case")
+	}
+	tenants := router.Host("{tenantID}.example.com")
+	tenants.Get("/status", func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := tenants.GetPathParams("tenantID", r)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(tenant))
+	})
+	router.Host("{code:[A-Z]+}.codes.example.com").Get("/status", methodHandler("codes"))
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+		body   string
+	}{
+		{name: "static labels", url: "https://api.EXAMPLE.com/status", status: http.StatusOK, body: "api:GET"},
+		{name: "variable name kept", url: "http://Acme.example.com/status", status: http.StatusOK, body: "acme"},
+		{name: "constraint kept", url: "http://abc.codes.example.com/status", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(GET, tt.url, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_HostPolicies(t *testing.T) {
+	router := NewRouter().AddMethods("PURGE").SetTrailingSlash(TrailingSlashRedirect).
+		SetRedirectCode(http.StatusPermanentRedirect).SetAutoHead(false)
+	api := router.Host("api.example.com")
+	api.Add("/cache", methodHandler("api"), "PURGE")
+	api.Get("/items/", methodHandler("api"))
+	//the changes made after the host is created do not apply to it
+	router.SetRedirectCode(http.StatusMovedPermanently)
+
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		path     string
+		location string
+	}{
+		{name: "added method", method: "PURGE", path: "/cache", status: http.StatusOK},
+		{
+			name:     "trailing slash redirect",
+			method:   GET,
+			path:     "/items",
+			status:   http.StatusPermanentRedirect,
+			location: "http://api.example.com/items/",
+		},
+		{name: "auto HEAD disabled", method: HEAD, path: "/items/", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, "http://api.example.com"+tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("Location = %v, want %v", location, tt.location)
+			}
+		})
+	}
+}
+
+func TestRouter_HostPanics(t *testing.T) {
+	for _, pattern := range []string{"ftp://files.example.com", "api..example.com", "api.example.com:", "a:b:c", "{path...}.example.com"} {
+		t.Run(pattern, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Host(%s) did not panic", pattern)
+				}
+			}()
+			NewRouter().Host(pattern)
+		})
+	}
+}
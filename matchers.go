@@ -0,0 +1,190 @@
+package turbo
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//Matcher is a predicate on the request selecting a variant of the handler of a route
+type Matcher struct {
+	//status of the response when no variant matches and this matcher rejected the request
+	status int
+	match  func(r *http.Request) bool
+}
+
+//MatcherFunc creates a Matcher from the predicate, the status is sent when no variant matches the request because of
+//this matcher
+func MatcherFunc(status int, match func(r *http.Request) bool) Matcher {
+	return Matcher{status: status, match: match}
+}
+
+//Accept matches the requests accepting one of the media types as per their Accept header, the requests without the
+//header accept any media type. The requests not matching are answered with 406 Not Acceptable.
+func Accept(mediaTypes ...string) Matcher {
+	return MatcherFunc(http.StatusNotAcceptable, func(r *http.Request) bool {
+		accept := r.Header.Get("Accept")
+		if accept == "" {
+			return true
+		}
+		for _, accepted := range strings.Split(accept, ",") {
+			mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err != nil {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+				continue
+			}
+			for _, mediaType := range mediaTypes {
+				if mediaTypeMatches(mediaRange, mediaType) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+//ContentType matches the requests whose Content-Type is one of the media types, the media types can be ranges such as
+//text/*. The requests not matching are answered with 415 Unsupported Media Type.
+func ContentType(mediaTypes ...string) Matcher {
+	return MatcherFunc(http.StatusUnsupportedMediaType, func(r *http.Request) bool {
+		contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			return false
+		}
+		for _, mediaType := range mediaTypes {
+			if mediaTypeMatches(mediaType, contentType) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+//Header matches the requests having the header with the value, any value matches if the value is empty.
+//The requests not matching are answered with 400 Bad Request.
+func Header(name, value string) Matcher {
+	return MatcherFunc(http.StatusBadRequest, func(r *http.Request) bool {
+		values, ok := r.Header[http.CanonicalHeaderKey(name)]
+		return ok && (value == "" || contains(values, value))
+	})
+}
+
+//Query matches the requests having the query parameter with the value, any value matches if the value is empty.
+//The requests not matching are answered with 400 Bad Request.
+func Query(name, value string) Matcher {
+	return MatcherFunc(http.StatusBadRequest, func(r *http.Request) bool {
+		values, ok := r.URL.Query()[name]
+		return ok && (value == "" || contains(values, value))
+	})
+}
+
+//contains checks if the values contain the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//mediaTypeMatches checks if the media type is within the media range such as */*, text/* or text/csv
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	mediaRange = strings.ToLower(mediaRange)
+	mediaType = strings.ToLower(mediaType)
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
+
+//variant is a handler selected by its matchers
+type variant struct {
+	matchers []Matcher
+	handler  http.Handler
+}
+
+//variantHandler serves the request with the first variant whose matchers all match it, or with the handler added
+//without matchers if none does
+type variantHandler struct {
+	variants []*variant
+	fallback http.Handler
+}
+
+func (v *variantHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := 0
+	best := -1
+	for _, candidate := range v.variants {
+		matched := 0
+		for _, m := range candidate.matchers {
+			if !m.match(r) {
+				//the status of the variant that got the farthest is sent if none matches
+				if matched > best {
+					best = matched
+					status = m.status
+				}
+				break
+			}
+			matched++
+		}
+		if matched == len(candidate.matchers) {
+			candidate.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	if v.fallback != nil {
+		v.fallback.ServeHTTP(w, r)
+		return
+	}
+	variantNotMatched(w, r, status)
+}
+
+//AddVariant adds a handler for the method that serves the requests matching all the matchers, the variants are
+//evaluated in the order they are added and the handler added for the method without matchers serves the requests
+//not matching any. When no handler serves the request the status of the matcher that rejected it is sent, such as
+//406 Not Acceptable for Accept or 415 Unsupported Media Type for ContentType.
+//The method has to be supported by the router, as one of the Methods or added with Router.AddMethods, or already
+//registered on the route.
+func (route *Route) AddVariant(method string, f func(w http.ResponseWriter, r *http.Request), matchers ...Matcher) *Route {
+	return route.update(func() {
+		_, registered := route.handlers[method]
+		if !route.router.supports(method) && !registered {
+			panic(fmt.Sprintf("Invalid/Unsupported Http method  %s provided", method))
+		}
+		handler := route.variants(method)
+		handler.variants = append(handler.variants, &variant{matchers: matchers, handler: http.HandlerFunc(f)})
+		route.handlers[method] = handler
+	})
+}
+
+//AddMatcher restricts the handler added for the method without matchers to the requests matching all the matchers,
+//the handler is evaluated after the variants added before. The requests not matching any variant are then answered
+//with the status of the matcher that rejected them.
+func (route *Route) AddMatcher(method string, matchers ...Matcher) *Route {
+	return route.update(func() {
+		handler := route.variants(method)
+		if handler.fallback == nil {
+			panic(fmt.Sprintf("no handler without matchers for the method %s", method))
+		}
+		handler.variants = append(handler.variants, &variant{matchers: matchers, handler: handler.fallback})
+		handler.fallback = nil
+		route.handlers[method] = handler
+	})
+}
+
+//variants returns a copy of the variant handler of the method to change, the handler being served is left as is
+func (route *Route) variants(method string) *variantHandler {
+	current := route.handlers[method]
+	if handler, ok := current.(*variantHandler); ok {
+		variants := make([]*variant, 0, len(handler.variants)+1)
+		return &variantHandler{variants: append(variants, handler.variants...), fallback: handler.fallback}
+	}
+	return &variantHandler{fallback: current}
+}
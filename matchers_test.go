@@ -0,0 +1,187 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoute_AddVariant(t *testing.T) {
+	router := NewRouter()
+	router.Get("/reports", methodHandler("default")).
+		AddVariant(GET, methodHandler("csv"), Accept("text/csv")).
+		AddVariant(GET, methodHandler("v2"), Header("X-API-Version", "2"), Accept("application/json")).
+		AddVariant(GET, methodHandler("preview"), Query("preview", ""))
+	router.Post("/uploads", methodHandler("default")).
+		AddVariant(POST, methodHandler("image"), ContentType("image/*")).
+		AddVariant(POST, methodHandler("json"), ContentType("application/json"))
+	router.Get("/exports", methodHandler("json")).
+		AddFilter(traceFilter("filter")).
+		AddMatcher(GET, Accept("application/json")).
+		AddVariant(GET, methodHandler("csv"), Accept("text/csv"))
+	router.Put("/imports", methodHandler("json")).
+		AddMatcher(PUT, Header("X-API-Version", "2"), ContentType("application/json")).
+		AddVariant(PUT, methodHandler("v3"), Header("X-API-Version", "3"))
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{
+			name:    "accept variant",
+			path:    "/reports",
+			headers: map[string]string{"Accept": "text/html;q=0.9, text/csv"},
+			status:  http.StatusOK,
+			body:    "csv:GET",
+		},
+		{
+			name:    "accept range",
+			path:    "/reports",
+			headers: map[string]string{"Accept": "text/*"},
+			status:  http.StatusOK,
+			body:    "csv:GET",
+		},
+		{
+			name:    "all the matchers of the variant",
+			path:    "/reports",
+			headers: map[string]string{"X-API-Version": "2", "Accept": "application/json"},
+			status:  http.StatusOK,
+			body:    "v2:GET",
+		},
+		{
+			name:    "query variant",
+			path:    "/reports?preview",
+			headers: map[string]string{"Accept": "application/xml"},
+			status:  http.StatusOK,
+			body:    "preview:GET",
+		},
+		{
+			name:    "fallback",
+			path:    "/reports",
+			headers: map[string]string{"Accept": "application/xml"},
+			status:  http.StatusOK,
+			body:    "default:GET",
+		},
+		{
+			name:    "content type range",
+			method:  POST,
+			path:    "/uploads",
+			headers: map[string]string{"Content-Type": "image/png"},
+			status:  http.StatusOK,
+			body:    "image:POST",
+		},
+		{
+			name:    "content type with parameters",
+			method:  POST,
+			path:    "/uploads",
+			headers: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			status:  http.StatusOK,
+			body:    "json:POST",
+		},
+		{
+			name:    "matcher added to the handler",
+			path:    "/exports",
+			headers: map[string]string{"Accept": "application/json"},
+			status:  http.StatusOK,
+			body:    "json:GET",
+		},
+		{
+			name:    "variant added after the matcher",
+			path:    "/exports",
+			headers: map[string]string{"Accept": "text/csv"},
+			status:  http.StatusOK,
+			body:    "csv:GET",
+		},
+		{
+			name:    "not acceptable",
+			path:    "/exports",
+			headers: map[string]string{"Accept": "application/xml, text/csv;q=0"},
+			status:  http.StatusNotAcceptable,
+		},
+		{
+			name:   "no accept header accepts any",
+			path:   "/exports",
+			status: http.StatusOK,
+			body:   "json:GET",
+		},
+		{
+			name:    "unsupported media type after the header matched",
+			method:  PUT,
+			path:    "/imports",
+			headers: map[string]string{"X-API-Version": "2", "Content-Type": "text/plain"},
+			status:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:    "header not matching",
+			method:  PUT,
+			path:    "/imports",
+			headers: map[string]string{"X-API-Version": "1", "Content-Type": "application/json"},
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "header variant",
+			method:  PUT,
+			path:    "/imports",
+			headers: map[string]string{"X-API-Version": "3"},
+			status:  http.StatusOK,
+			body:    "v3:PUT",
+		},
+		{
+			name:   "method without variants",
+			method: DELETE,
+			path:   "/imports",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "HEAD uses the GET variants",
+			method:  HEAD,
+			path:    "/reports",
+			headers: map[string]string{"Accept": "text/csv"},
+			status:  http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = GET
+			}
+			r := httptest.NewRequest(method, tt.path, nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRoute_AddVariantPanics(t *testing.T) {
+	router := NewRouter()
+	for name, add := range map[string]func(){
+		"unknown method":          func() { router.Get("/a", testHandler).AddVariant("PURGE", testHandler) },
+		"matcher with no handler": func() { router.Get("/b", testHandler).AddMatcher(POST, Accept("text/csv")) },
+		"matcher added twice": func() {
+			router.Get("/c", testHandler).AddMatcher(GET, Accept("text/csv")).AddMatcher(GET, Accept("text/html"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			add()
+		})
+	}
+}
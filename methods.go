@@ -0,0 +1,144 @@
+package turbo
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//AllowHeader lists the methods supported by a route
+const AllowHeader = "Allow"
+
+//anyMethod is the key of the handler added with Any in the handlers of the route
+const anyMethod = "*"
+
+//AddMethods extends the methods accepted by this router beyond the Methods, such as the WebDAV methods PROPFIND and
+//MKCOL. The methods are case-sensitive and must be valid tokens as per RFC 9110.
+func (router *Router) AddMethods(methods ...string) *Router {
+	for _, method := range methods {
+		if !isToken(method) {
+			panic(fmt.Sprintf("Invalid/Unsupported Http method  %s provided", method))
+		}
+	}
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	extended := make(map[string]bool, len(router.methods)+len(methods))
+	for method := range router.methods {
+		extended[method] = true
+	}
+	for _, method := range methods {
+		extended[method] = true
+	}
+	router.methods = extended
+	return router
+}
+
+//supports checks if the method can be registered on this router
+func (router *Router) supports(method string) bool {
+	if _, ok := Methods[method]; ok {
+		return true
+	}
+	return method == anyMethod || router.methods[method]
+}
+
+//isToken checks if the value is a token as per RFC 9110
+func isToken(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("!#$%&'*+-.^_`|~", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+//SetAutoHead sets whether HEAD requests are answered by the GET handler of the route with the body suppressed, this
+//is enabled by default. A HEAD handler registered on the route is used regardless.
+func (router *Router) SetAutoHead(enabled bool) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.autoHead = enabled
+	return router
+}
+
+//SetAutoOptions sets whether OPTIONS requests are answered with 204 No Content and the Allow header listing the
+//methods of the route, this is enabled by default. An OPTIONS handler registered on the route is used regardless.
+//The filters and the authenticator of the route are not applied to the automatic OPTIONS responses.
+func (router *Router) SetAutoOptions(enabled bool) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.autoOptions = enabled
+	return router
+}
+
+//SetAllowHeader sets whether the 405 Method Not Allowed responses have the Allow header listing the methods of the
+//route as required by RFC 9110, this is enabled by default.
+func (router *Router) SetAllowHeader(enabled bool) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.allowHeader = enabled
+	return router
+}
+
+//allowedMethods returns the sorted methods the route answers including the automatic HEAD and OPTIONS
+func (router *Router) allowedMethods(route *Route) []string {
+	if len(route.handlers) == 0 {
+		return nil
+	}
+	allowed := make([]string, 0, len(route.handlers)+2)
+	for method := range route.handlers {
+		if method != anyMethod {
+			allowed = append(allowed, method)
+		}
+	}
+	if _, ok := route.handlers[HEAD]; !ok && router.autoHead && route.handlers[GET] != nil {
+		allowed = append(allowed, HEAD)
+	}
+	if _, ok := route.handlers[OPTIONS]; !ok && router.autoOptions {
+		allowed = append(allowed, OPTIONS)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+//optionsHandler answers OPTIONS with the methods of the route
+func (router *Router) optionsHandler(route *Route) http.Handler {
+	allowed := strings.Join(router.allowedMethods(route), ", ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(AllowHeader, allowed)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+//connectTarget sets the path of a CONNECT request to its target host:port prefixed with a /, the requests having the
+//authority as target have no path and are routed as /host:port
+func connectTarget(r *http.Request) *http.Request {
+	if r.Method != CONNECT || r.URL.Path != "" || r.URL.Host == "" {
+		return r
+	}
+	r = r.WithContext(r.Context())
+	u := *r.URL
+	u.Path = PathSeparator + u.Host
+	r.URL = &u
+	return r
+}
+
+//bodylessResponseWriter drops the body written to the response
+type bodylessResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *bodylessResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+//suppressBody wraps the handler so that the body it writes is not sent
+func suppressBody(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(&bodylessResponseWriter{w}, r)
+	})
+}
@@ -0,0 +1,249 @@
+package turbo
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_AutoHeadAndOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(router *Router)
+		method    string
+		path      string
+		status    int
+		allow     string
+		body      string
+	}{
+		{
+			name:   "HEAD served by GET",
+			method: HEAD,
+			path:   "/users",
+			status: http.StatusOK,
+		},
+		{
+			name:   "HEAD handler takes precedence",
+			method: HEAD,
+			path:   "/posts",
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "HEAD without GET",
+			method: HEAD,
+			path:   "/orders",
+			status: http.StatusMethodNotAllowed,
+			allow:  "OPTIONS, POST",
+		},
+		{
+			name:      "HEAD disabled",
+			configure: func(router *Router) { router.SetAutoHead(false) },
+			method:    HEAD,
+			path:      "/users",
+			status:    http.StatusMethodNotAllowed,
+			allow:     "DELETE, GET, OPTIONS",
+		},
+		{
+			name:   "OPTIONS",
+			method: OPTIONS,
+			path:   "/users",
+			status: http.StatusNoContent,
+			allow:  "DELETE, GET, HEAD, OPTIONS",
+		},
+		{
+			name:      "OPTIONS disabled",
+			configure: func(router *Router) { router.SetAutoOptions(false) },
+			method:    OPTIONS,
+			path:      "/users",
+			status:    http.StatusMethodNotAllowed,
+			allow:     "DELETE, GET, HEAD",
+		},
+		{
+			name:   "OPTIONS skips the authenticator",
+			method: OPTIONS,
+			path:   "/secure",
+			status: http.StatusNoContent,
+			allow:  "GET, HEAD, OPTIONS",
+		},
+		{
+			name:   "Allow on 405",
+			method: PUT,
+			path:   "/users",
+			status: http.StatusMethodNotAllowed,
+			allow:  "DELETE, GET, HEAD, OPTIONS",
+		},
+		{
+			name:      "Allow disabled",
+			configure: func(router *Router) { router.SetAllowHeader(false) },
+			method:    PUT,
+			path:      "/users",
+			status:    http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "GET",
+			method: GET,
+			path:   "/users",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			router.Add("/users", testHandler, GET, DELETE)
+			router.Get("/posts", testHandler)
+			router.Add("/posts", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			}, HEAD)
+			router.Post("/orders", testHandler)
+			router.Get("/secure", testHandler).AddAuthenticator(headerAuthenticator("token"))
+			if tt.configure != nil {
+				tt.configure(router)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if allow := w.Header().Get(AllowHeader); allow != tt.allow {
+				t.Errorf("Allow = %v, want %v", allow, tt.allow)
+			}
+			if w.Body.String() != tt.body && tt.status != http.StatusMethodNotAllowed {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+//methodHandler writes the method of the request
+func methodHandler(name string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + ":" + r.Method))
+	}
+}
+
+func TestRouter_AddMethods(t *testing.T) {
+	router := NewRouter().AddMethods("PROPFIND", "MKCOL")
+	router.Add("/dav/{path...}", methodHandler("dav"), "PROPFIND", "MKCOL", GET)
+	router.Add("/{authority}", methodHandler("tunnel"), CONNECT)
+	router.Get("/props", methodHandler("props")).AddVariant("PROPFIND", methodHandler("variant"), Accept("text/xml"))
+	other := NewRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		allow  string
+	}{
+		{name: "PROPFIND", method: "PROPFIND", path: "/dav/docs", status: http.StatusOK, body: "dav:PROPFIND"},
+		{name: "MKCOL", method: "MKCOL", path: "/dav/docs/new", status: http.StatusOK, body: "dav:MKCOL"},
+		{name: "CONNECT", method: CONNECT, path: "example.com:443", status: http.StatusOK, body: "tunnel:CONNECT"},
+		{name: "variant", method: "PROPFIND", path: "/props", status: http.StatusOK, body: "variant:PROPFIND"},
+		{
+			name:   "Allow lists the custom methods",
+			method: DELETE,
+			path:   "/dav/docs",
+			status: http.StatusMethodNotAllowed,
+			allow:  "GET, HEAD, MKCOL, OPTIONS, PROPFIND",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+			if allow := w.Header().Get(AllowHeader); allow != tt.allow {
+				t.Errorf("Allow = %v, want %v", allow, tt.allow)
+			}
+		})
+	}
+
+	for name, add := range map[string]func(){
+		"method of another router": func() { other.Add("/dav", testHandler, "PROPFIND") },
+		"variant of another router": func() {
+			other.Get("/props", testHandler).AddVariant("PROPFIND", testHandler)
+		},
+		"invalid method": func() { router.AddMethods("BAD METHOD") },
+		"empty method":   func() { router.AddMethods("") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			add()
+		})
+	}
+}
+
+func TestRouter_Any(t *testing.T) {
+	router := NewRouter().AddMethods("PURGE")
+	router.Any("/proxy/{path...}", methodHandler("any"))
+	router.Post("/proxy/{path...}", methodHandler("post"))
+	api := router.Group("/api", filterFunction("api/"))
+	api.Any("/echo", methodHandler("echo"))
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: GET, path: "/proxy/users", body: "any:GET"},
+		{method: PATCH, path: "/proxy/users", body: "any:PATCH"},
+		{method: "PURGE", path: "/proxy/cache", body: "any:PURGE"},
+		{method: OPTIONS, path: "/proxy/users", body: "any:OPTIONS"},
+		{method: POST, path: "/proxy/users", body: "post:POST"},
+		{method: DELETE, path: "/api/echo", body: "api/echo:DELETE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %v, want %v", w.Code, http.StatusOK)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_Connect(t *testing.T) {
+	router := NewRouter()
+	router.Add("/{authority}", func(w http.ResponseWriter, r *http.Request) {
+		authority, _ := router.GetPathParams("authority", r)
+		w.Header().Set("X-Authority", authority)
+		w.WriteHeader(http.StatusOK)
+	}, CONNECT)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: CONNECT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %v, want %v", res.StatusCode, http.StatusOK)
+	}
+	if authority := res.Header.Get("X-Authority"); authority != "example.com:443" {
+		t.Errorf("X-Authority = %v, want example.com:443", authority)
+	}
+}
@@ -0,0 +1,144 @@
+package turbo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+)
+
+//mount is a handler serving every path under a prefix
+type mount struct {
+	//prefix as it was mounted, normalised to start with a / and not end with one
+	prefix string
+	//segments of the prefix, matched as the segments of a route
+	segments []*Route
+	handler  http.Handler
+}
+
+//Mount serves every request under the prefix with the handler, the handler can be an http.Handler like
+//http.FileServer or another Router. The prefix is stripped from the path of the request, the path variables of the
+//prefix are added to the path params of the request and are available to the routes of a mounted Router.
+//The routes of the router take precedence over the mounts, a path matching a route is never passed to a mount even if
+//the route does not handle the method of the request. When several mounts match, the longest prefix is used.
+func (router *Router) Mount(prefix string, handler http.Handler) *Router {
+	if handler == nil {
+		panic("cannot mount a nil handler")
+	}
+	m := &mount{prefix: groupPrefix(prefix), handler: handler}
+	if m.prefix != textutils.EmptyStr {
+		for _, segment := range splitPath(m.prefix) {
+			route := newSegmentRoute(segment, false)
+			if segment == textutils.EmptyStr || route.isCatchAll {
+				panic(fmt.Sprintf("invalid mount prefix %s", prefix))
+			}
+			m.segments = append(m.segments, route)
+		}
+	}
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	for _, existing := range router.mounts {
+		if existing.prefix == m.prefix {
+			panic(fmt.Sprintf("a handler is already mounted at %s", prefix))
+		}
+	}
+	logger.InfoF("Mounting Handler: %s", m.prefix+PathSeparator)
+	mounts := make([]*mount, 0, len(router.mounts)+1)
+	mounts = append(mounts, router.mounts...)
+	mounts = append(mounts, m)
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return len(mounts[i].segments) > len(mounts[j].segments)
+	})
+	router.mounts = mounts
+	return router
+}
+
+//match checks if the path is under the prefix of the mount and returns the path left after the prefix
+func (m *mount) match(path string) (string, []Param, bool) {
+	var params []Param
+	rest := path
+	for _, segment := range m.segments {
+		if !strings.HasPrefix(rest, PathSeparator) {
+			return textutils.EmptyStr, nil, false
+		}
+		rest = rest[1:]
+		value := rest
+		if idx := strings.Index(rest, PathSeparator); idx >= 0 {
+			value = rest[:idx]
+			rest = rest[idx:]
+		} else {
+			rest = textutils.EmptyStr
+		}
+		if !segment.matches(value) {
+			return textutils.EmptyStr, nil, false
+		}
+		if segment.isPathVar {
+			params = append(params, Param{key: segment.path, value: value})
+		}
+	}
+	if rest == textutils.EmptyStr {
+		rest = PathSeparator
+	}
+	return rest, params, true
+}
+
+//findMount returns the handler of the mount matching the request with the request to pass to it, it is called with
+//the lock held
+func (router *Router) findMount(r *http.Request) (http.Handler, *http.Request) {
+	for _, m := range router.mounts {
+		rest, params, ok := m.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		r = withParams(r, params)
+		if r.URL.Path != rest {
+			r = r.WithContext(r.Context())
+			r.URL = m.strip(r.URL, rest)
+		}
+		return m.handler, r
+	}
+	return nil, r
+}
+
+//strip returns a copy of the url with the path left after the prefix, the escaped path is stripped of the segments of
+//the prefix as well so that the escaped separators such as %2F of the path left are kept
+func (m *mount) strip(u *url.URL, rest string) *url.URL {
+	stripped := *u
+	stripped.Path = rest
+	stripped.RawPath = textutils.EmptyStr
+	escaped := u.EscapedPath()
+	for range m.segments {
+		if !strings.HasPrefix(escaped, PathSeparator) {
+			return &stripped
+		}
+		escaped = escaped[1:]
+		if idx := strings.Index(escaped, PathSeparator); idx >= 0 {
+			escaped = escaped[idx:]
+		} else {
+			escaped = textutils.EmptyStr
+		}
+	}
+	//an escaped separator in the prefix shifts the segments, the escaped path is only kept if it encodes the path left
+	if unescaped, err := url.PathUnescape(escaped); err == nil && unescaped == rest {
+		stripped.RawPath = escaped
+	}
+	return &stripped
+}
+
+//withParams adds the path params to the request, the params of the request set by the routers it is mounted under
+//are kept ahead of the new ones
+func withParams(r *http.Request, params []Param) *http.Request {
+	if len(params) == 0 {
+		return r
+	}
+	if parent, ok := r.Context().Value("params").([]Param); ok && len(parent) > 0 {
+		merged := make([]Param, 0, len(parent)+len(params))
+		merged = append(merged, parent...)
+		params = append(merged, params...)
+	}
+	return r.WithContext(context.WithValue(r.Context(), "params", params))
+}
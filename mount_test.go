@@ -0,0 +1,177 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+//pathHandler writes the name with the path of the request it receives
+func pathHandler(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + ":" + r.URL.Path))
+	})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	teams := NewRouter()
+	teams.Get("/members/{member}", func(w http.ResponseWriter, r *http.Request) {
+		team, _ := teams.GetPathParams("team", r)
+		member, _ := teams.GetPathParams("member", r)
+		w.Write([]byte(team + "/" + member))
+	})
+	router := NewRouter()
+	router.Get("/static/index", testHandler)
+	router.Post("/assets/upload", testHandler)
+	router.Get("/assets/css/site", testHandler)
+	router.Mount("/static/", pathHandler("static"))
+	router.Mount("/static/images", pathHandler("images"))
+	router.Mount("/assets", pathHandler("assets"))
+	router.Mount("/teams/{team}", teams)
+	router.Mount("/raw", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + " " + r.URL.EscapedPath()))
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "prefix stripped",
+			path:   "/static/css/site.css",
+			status: http.StatusOK,
+			body:   "static:/css/site.css",
+		},
+		{
+			name:   "prefix only",
+			path:   "/static",
+			status: http.StatusOK,
+			body:   "static:/",
+		},
+		{
+			name:   "trailing slash kept",
+			path:   "/static/css/",
+			status: http.StatusOK,
+			body:   "static:/css/",
+		},
+		{
+			name:   "longest prefix",
+			path:   "/static/images/logo.png",
+			status: http.StatusOK,
+			body:   "images:/logo.png",
+		},
+		{
+			name:   "route takes precedence",
+			path:   "/static/index",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "route without the method",
+			path:   "/assets/upload",
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "path between the routes",
+			path:   "/assets/css",
+			status: http.StatusOK,
+			body:   "assets:/css",
+		},
+		{
+			name:   "segment not matching the prefix",
+			path:   "/staticfiles/site.css",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "escaped separator kept",
+			path:   "/raw/a%2Fb/c",
+			status: http.StatusOK,
+			body:   "/a/b/c /a%2Fb/c",
+		},
+		{
+			name:   "mounted router with the params of the prefix",
+			path:   "/teams/core/members/jane",
+			status: http.StatusOK,
+			body:   "core/jane",
+		},
+		{
+			name:   "mounted router not found",
+			path:   "/teams/core/projects",
+			status: http.StatusNotFound,
+			body:   "Endpoint Not Found : /projects\n",
+		},
+		{
+			name:   "mounted router method not allowed",
+			method: POST,
+			path:   "/teams/core/members/jane",
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = GET
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_MountRoot(t *testing.T) {
+	router := NewRouter()
+	router.Get("/api", testHandler)
+	router.Mount("/", pathHandler("root"))
+	for path, want := range map[string]string{"/api": "testHandler", "/index.html": "root:/index.html"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(GET, path, nil))
+		if w.Body.String() != want {
+			t.Errorf("%s body = %v, want %v", path, w.Body.String(), want)
+		}
+	}
+}
+
+func TestRouter_MountPanics(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		handler http.Handler
+	}{
+		{
+			name:   "nil handler",
+			prefix: "/assets",
+		},
+		{
+			name:    "duplicate prefix",
+			prefix:  "/static/",
+			handler: testHandler,
+		},
+		{
+			name:    "empty variable",
+			prefix:  "/teams/{}",
+			handler: testHandler,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			router.Mount("/static", testHandler)
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Mount(%s) did not panic", tt.prefix)
+				}
+			}()
+			router.Mount(tt.prefix, tt.handler)
+		})
+	}
+}
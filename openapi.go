@@ -0,0 +1,197 @@
+package turbo
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//OpenAPI generates the OAS document describing the routes registered with this router.
+//Every registered method of a route is added as an operation with the path variables and the query parameters
+//declared on the route as its parameters, unless an operation has been set for the method using SetOperation.
+func (router *Router) OpenAPI(info spec.Info) *spec.OAS {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	oas := &spec.OAS{
+		OpenAPI: spec.Version,
+		Info:    info,
+		Paths:   make(map[string]*spec.PathItem),
+	}
+	for _, key := range sortedRouteKeys(router.topLevelRoutes) {
+		router.topLevelRoutes[key].describe(textutils.EmptyStr, nil, oas.Paths)
+	}
+	return oas
+}
+
+//describe adds the path items of the route and its sub routes to the paths. The constrained variables at the same
+//level such as /users/{id:int} and /users/{id:uuid} share the template /users/{id}, their operations are merged in the
+//order of their keys and the methods already described by a sibling are logged and skipped.
+func (route *Route) describe(parent string, pathParams []*spec.Parameter, paths map[string]*spec.PathItem) {
+	template := parent + PathSeparator + route.path
+	if route.isPathVar {
+		template = parent + PathSeparator + textutils.OpenBraceStr + route.path + textutils.CloseBraceStr
+		//copy the parent params so that the siblings do not share the backing array
+		params := make([]*spec.Parameter, 0, len(pathParams)+1)
+		params = append(params, pathParams...)
+		pathParams = append(params, &spec.Parameter{
+			Name:     route.path,
+			In:       "path",
+			Required: true,
+			Schema:   route.schema(),
+		})
+	}
+	if len(route.handlers) > 0 {
+		item, ok := paths[template]
+		if !ok {
+			item = &spec.PathItem{}
+		}
+		described := item.Operations()
+		methods := make([]string, 0, len(route.handlers))
+		for method := range route.handlers {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			//the handlers of every method cannot be described in OAS
+			if method == anyMethod {
+				continue
+			}
+			if described[method] != nil {
+				route.logger.ErrorF("Method %s of path %s is already described by another route", method, template)
+				continue
+			}
+			op := route.operations[method]
+			if op == nil {
+				op = &spec.Operation{}
+				op.Parameters = append(op.Parameters, pathParams...)
+				op.Parameters = append(op.Parameters, route.queryParameters()...)
+			}
+			if !item.SetOperation(method, op) {
+				route.logger.ErrorF("Method %s of path %s cannot be described in OAS", method, template)
+			}
+		}
+		paths[template] = item
+	}
+	for _, key := range sortedRouteKeys(route.subRoutes) {
+		route.subRoutes[key].describe(template, pathParams, paths)
+	}
+}
+
+//queryParameters returns the OAS parameters of the query params declared on the route sorted by name
+func (route *Route) queryParameters() []*spec.Parameter {
+	params := make([]*spec.Parameter, 0, len(route.queryParams))
+	for _, q := range route.queryParams {
+		params = append(params, &spec.Parameter{
+			Name:     q.name,
+			In:       "query",
+			Required: q.required,
+			Schema:   &spec.Schema{Type: "string"},
+		})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+	return params
+}
+
+//MissingHandlerError is returned by AddSpec when the operations of the OAS document cannot be bound to a handler
+type MissingHandlerError struct {
+	//Operations that do not have a handler in the <METHOD> <path> (operationId: <id>) format
+	Operations []string
+}
+
+func (e *MissingHandlerError) Error() string {
+	return fmt.Sprintf("no handlers found for %d operation(s):\n\t%s", len(e.Operations), strings.Join(e.Operations, "\n\t"))
+}
+
+//PathConflictError is returned by AddSpec when the paths of the OAS document cannot be registered, such as /pets/{id}
+//and /pets/{petId}/photos that name the same path variable differently
+type PathConflictError struct {
+	//Paths that cannot be registered with the reason
+	Paths []string
+}
+
+func (e *PathConflictError) Error() string {
+	return fmt.Sprintf("%d path(s) cannot be registered:\n\t%s", len(e.Paths), strings.Join(e.Paths, "\n\t"))
+}
+
+//AddSpec registers every operation of the OAS document on the router binding it to the handler of its operationId.
+//The routes are registered only if all the operations have a handler, else a *MissingHandlerError listing every
+//unbound operation is returned, and only if all the paths can be registered, else a *PathConflictError listing every
+//conflicting path is returned. The operations are set on the routes for the response validation.
+func (router *Router) AddSpec(oas *spec.OAS, handlers map[string]http.HandlerFunc) error {
+	paths := make([]string, 0, len(oas.Paths))
+	for path := range oas.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var missing []string
+	for _, path := range paths {
+		ops := oas.Paths[path].Operations()
+		for _, method := range sortedMethods(ops) {
+			if handlers[ops[method].OperationID] == nil {
+				missing = append(missing, fmt.Sprintf("%s %s (operationId: %s)", method, path, ops[method].OperationID))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingHandlerError{Operations: missing}
+	}
+	//the paths are checked against the routes of the router and against each other before any is registered, the
+	//lock is held till all are registered so that no route added meanwhile can conflict with them
+	var conflicts []string
+	added := &Router{topLevelRoutes: make(map[string]*Route)}
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	for _, path := range paths {
+		err := router.check(path)
+		if err == nil {
+			if err = added.check(path); err == nil {
+				added.route(path)
+			}
+		}
+		if err != nil {
+			conflicts = append(conflicts, err.Error())
+		}
+	}
+	if len(conflicts) > 0 {
+		return &PathConflictError{Paths: conflicts}
+	}
+	for _, path := range paths {
+		ops := oas.Paths[path].Operations()
+		for _, method := range sortedMethods(ops) {
+			router.add(path, handlers[ops[method].OperationID], false, nil, []string{method}).setOperation(method, ops[method])
+		}
+	}
+	return nil
+}
+
+//sortedMethods returns the methods of the operations in a stable order
+func sortedMethods(ops map[string]*spec.Operation) []string {
+	methods := make([]string, 0, len(ops))
+	for method := range ops {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+//SetOperation sets the OAS operation describing the handler of the method, the operation is used for the response
+//validation of the route.
+func (route *Route) SetOperation(method string, op *spec.Operation) *Route {
+	return route.update(func() {
+		route.setOperation(method, op)
+	})
+}
+
+//setOperation sets the OAS operation of the method, it is called with the lock held
+func (route *Route) setOperation(method string, op *spec.Operation) {
+	if route.operations == nil {
+		route.operations = make(map[string]*spec.Operation)
+	}
+	route.operations[method] = op
+}
@@ -0,0 +1,168 @@
+package turbo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func TestRouter_OpenAPI(t *testing.T) {
+	var router = NewRouter()
+	router.Get("/api/v1/users", dummyHandler).addQueryVar("page", false).addQueryVar("limit", true)
+	router.Post("/api/v1/users", dummyHandler)
+	router.Get("/api/v1/users/{id}", dummyHandler)
+	router.Add("/api/v1/users/{id}/roles/:role", dummyHandler, PUT, DELETE)
+
+	oas := router.OpenAPI(spec.Info{Title: "Users", Version: "1.0.0"})
+	if oas.OpenAPI != spec.Version {
+		t.Errorf("OpenAPI() version = %v, want %v", oas.OpenAPI, spec.Version)
+	}
+	if len(oas.Paths) != 3 {
+		t.Fatalf("OpenAPI() paths = %v, want 3", len(oas.Paths))
+	}
+
+	users := oas.Paths["/api/v1/users"]
+	if users == nil || users.Get == nil || users.Post == nil || users.Put != nil {
+		t.Fatalf("OpenAPI() /api/v1/users operations = %+v", users)
+	}
+	if len(users.Get.Parameters) != 2 {
+		t.Fatalf("OpenAPI() query params = %v, want 2", len(users.Get.Parameters))
+	}
+	if p := users.Get.Parameters[0]; p.Name != "limit" || p.In != "query" || !p.Required {
+		t.Errorf("OpenAPI() query param = %+v", p)
+	}
+	if p := users.Get.Parameters[1]; p.Name != "page" || p.Required {
+		t.Errorf("OpenAPI() query param = %+v", p)
+	}
+
+	user := oas.Paths["/api/v1/users/{id}"]
+	if user == nil || user.Get == nil || len(user.Get.Parameters) != 1 {
+		t.Fatalf("OpenAPI() /api/v1/users/{id} = %+v", user)
+	}
+	if p := user.Get.Parameters[0]; p.Name != "id" || p.In != "path" || !p.Required {
+		t.Errorf("OpenAPI() path param = %+v", p)
+	}
+
+	roles := oas.Paths["/api/v1/users/{id}/roles/{role}"]
+	if roles == nil || roles.Put == nil || roles.Delete == nil {
+		t.Fatalf("OpenAPI() /api/v1/users/{id}/roles/{role} = %+v", roles)
+	}
+	if len(roles.Put.Parameters) != 2 || roles.Put.Parameters[1].Name != "role" {
+		t.Errorf("OpenAPI() path params = %+v", roles.Put.Parameters)
+	}
+
+	data, err := oas.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["paths"].(map[string]interface{})["/api/v1/users/{id}"]; !ok {
+		t.Errorf("ToJSON() = %s", data)
+	}
+}
+
+func TestRouter_OpenAPIConstrainedSiblings(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id:int}", dummyHandler)
+	router.Add("/users/{id:uuid}", dummyHandler, GET, DELETE)
+
+	oas := router.OpenAPI(spec.Info{Title: "Users", Version: "1.0.0"})
+	user := oas.Paths["/users/{id}"]
+	if len(oas.Paths) != 1 || user == nil || user.Get == nil || user.Delete == nil {
+		t.Fatalf("OpenAPI() /users/{id} = %+v", user)
+	}
+	//the first sibling in the order of the keys describes the method they share
+	if schema := user.Get.Parameters[0].Schema; schema.Type != "integer" {
+		t.Errorf("OpenAPI() GET path param type = %v, want integer", schema.Type)
+	}
+	if schema := user.Delete.Parameters[0].Schema; schema.Format == nil || *schema.Format != "uuid" {
+		t.Errorf("OpenAPI() DELETE path param format = %v, want uuid", schema.Format)
+	}
+}
+
+func TestRouter_AddSpec(t *testing.T) {
+	oas, err := spec.ParseFile("api/spec/testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	err = router.AddSpec(oas, map[string]http.HandlerFunc{"listPets": dummyHandler})
+	missing, ok := err.(*MissingHandlerError)
+	if !ok {
+		t.Fatalf("AddSpec() error = %v, want *MissingHandlerError", err)
+	}
+	want := []string{"POST /pets (operationId: createPet)", "GET /pets/{petId} (operationId: showPetById)"}
+	if !reflect.DeepEqual(missing.Operations, want) {
+		t.Errorf("AddSpec() missing = %v, want %v", missing.Operations, want)
+	}
+	if len(router.topLevelRoutes) != 0 {
+		t.Errorf("AddSpec() registered routes with missing handlers")
+	}
+
+	err = router.AddSpec(oas, map[string]http.HandlerFunc{
+		"listPets":    dummyHandler,
+		"createPet":   dummyHandler,
+		"showPetById": dummyHandler,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{GET, "/pets", http.StatusOK},
+		{POST, "/pets", http.StatusOK},
+		{GET, "/pets/42", http.StatusOK},
+		{DELETE, "/pets/42", http.StatusMethodNotAllowed},
+	} {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		router.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("ServeHTTP() %s %s = %v, want %v", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRouter_AddSpecConflicts(t *testing.T) {
+	getOp := func(id string) *spec.PathItem {
+		return &spec.PathItem{Get: &spec.Operation{OperationID: id}}
+	}
+	oas := &spec.OAS{Paths: map[string]*spec.PathItem{
+		"/pets/{id}":             getOp("showPet"),
+		"/pets/{petId}/photos":   getOp("listPhotos"),
+		"/owners/{ownerId}/pets": getOp("listOwnerPets"),
+	}}
+	handlers := map[string]http.HandlerFunc{
+		"showPet":       dummyHandler,
+		"listPhotos":    dummyHandler,
+		"listOwnerPets": dummyHandler,
+	}
+	router := NewRouter()
+	router.Get("/owners/{id}", dummyHandler)
+	err := router.AddSpec(oas, handlers)
+	conflicts, ok := err.(*PathConflictError)
+	if !ok {
+		t.Fatalf("AddSpec() error = %v, want *PathConflictError", err)
+	}
+	want := []string{
+		"/owners/{ownerId}/pets: the path variable ownerId conflicts with the path variable id",
+		"/pets/{petId}/photos: the path variable petId conflicts with the path variable id",
+	}
+	if !reflect.DeepEqual(conflicts.Paths, want) {
+		t.Errorf("AddSpec() conflicts = %v, want %v", conflicts.Paths, want)
+	}
+	if _, ok := router.topLevelRoutes["pets"]; ok {
+		t.Errorf("AddSpec() registered routes with conflicting paths")
+	}
+}
@@ -0,0 +1,236 @@
+package turbo
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+const (
+	//wildcard matches any single segment of the path, as the last segment it captures the rest of the path
+	wildcard = "*"
+	//catchAllSuffix marks the path variable capturing the rest of the path as in /files/{path...}
+	catchAllSuffix = "..."
+)
+
+//constraint restricts the values of a path variable, a segment of the request path matches the variable only if it
+//satisfies the constraint
+type constraint struct {
+	//expr is the constraint as it was declared, the name of a typed constraint or a regular expression
+	expr  string
+	match func(value string) bool
+	//schema describes the values in the OAS
+	schema *spec.Schema
+}
+
+//typedConstraints are the constraints that can be referred by name as in /users/{id:int}
+var typedConstraints = map[string]*constraint{
+	"int": {
+		match: func(value string) bool {
+			_, err := strconv.ParseInt(value, 10, 64)
+			return err == nil
+		},
+		schema: &spec.Schema{Type: "integer"},
+	},
+	"float": {
+		match: func(value string) bool {
+			_, err := strconv.ParseFloat(value, 64)
+			return err == nil
+		},
+		schema: &spec.Schema{Type: "number"},
+	},
+	"bool": {
+		match: func(value string) bool {
+			_, err := strconv.ParseBool(value)
+			return err == nil
+		},
+		schema: &spec.Schema{Type: "boolean"},
+	},
+	"uuid": {
+		match:  regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+		schema: &spec.Schema{Type: "string", Format: &uuidFormat},
+	},
+}
+
+var uuidFormat = "uuid"
+
+//newConstraint creates the constraint of the expression, the expressions that are not the name of a typed constraint
+//are regular expressions that have to match the whole segment
+func newConstraint(name, expr string) *constraint {
+	if typed, ok := typedConstraints[expr]; ok {
+		return &constraint{expr: expr, match: typed.match, schema: typed.schema}
+	}
+	exp, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		panic(fmt.Sprintf("invalid constraint %s of the path variable %s: %v", expr, name, err))
+	}
+	pattern := expr
+	return &constraint{expr: expr, match: exp.MatchString, schema: &spec.Schema{Type: "string", Pattern: &pattern}}
+}
+
+//splitPath splits the path into its segments, the / within the braces of a path variable does not split the path.
+//The empty string before the leading / is not returned.
+func splitPath(path string) []string {
+	return splitTemplate(path, textutils.ForwardSlashChar)[1:]
+}
+
+//splitTemplate splits the template at the separator except within the braces of the variables
+func splitTemplate(template string, separator rune) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i, c := range template {
+		switch c {
+		case textutils.OpenBraceChar:
+			depth++
+		case textutils.CloseBraceChar:
+			if depth > 0 {
+				depth--
+			}
+		case separator:
+			if depth == 0 {
+				parts = append(parts, template[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, template[start:])
+}
+
+//varKey is the key of the path variable in the sub routes, the : keeps it apart from a static segment of the same name
+func varKey(name string) string {
+	return string(textutils.ColonChar) + name
+}
+
+//catchAllKey is the key of the catch-all variable in the sub routes
+func catchAllKey(name string) string {
+	return varKey(name) + catchAllSuffix
+}
+
+//newSegmentRoute creates the route of a segment of the path being registered. The path variables are declared as
+//{name}, {name:constraint} or with the : prefix as :name. A trailing * is a catch-all variable named *.
+func newSegmentRoute(segment string, last bool) *Route {
+	route := &Route{
+		path:        segment,
+		handlers:    make(map[string]http.Handler),
+		subRoutes:   make(map[string]*Route),
+		queryParams: make(map[string]*QueryParam),
+	}
+	variable := textutils.EmptyStr
+	if strings.HasPrefix(segment, textutils.OpenBraceStr) && strings.HasSuffix(segment, textutils.CloseBraceStr) {
+		variable = segment[1 : len(segment)-1]
+	} else if strings.HasPrefix(segment, string(textutils.ColonChar)) {
+		variable = segment[1:]
+	} else if segment == wildcard {
+		if last {
+			route.isPathVar = true
+			route.isCatchAll = true
+		} else {
+			route.isWildcard = true
+		}
+		return route
+	} else {
+		return route
+	}
+	route.isPathVar = true
+	route.path = variable
+	if idx := strings.IndexRune(variable, textutils.ColonChar); idx >= 0 {
+		route.path = variable[:idx]
+		route.constraint = newConstraint(route.path, variable[idx+1:])
+	}
+	if strings.HasSuffix(route.path, catchAllSuffix) {
+		route.path = strings.TrimSuffix(route.path, catchAllSuffix)
+		if !last {
+			panic(fmt.Sprintf("the catch-all variable %s must be the last segment of the path", route.path))
+		}
+		if route.constraint != nil {
+			panic(fmt.Sprintf("the catch-all variable %s cannot have a constraint", route.path))
+		}
+		route.isCatchAll = true
+	}
+	if route.path == textutils.EmptyStr {
+		panic(fmt.Sprintf("the path variable %s must have a name", segment))
+	}
+	return route
+}
+
+//key is the key of the route in the sub routes of its parent
+func (route *Route) key() string {
+	switch {
+	case route.isCatchAll:
+		return catchAllKey(route.path)
+	case route.isWildcard:
+		return wildcard
+	case route.isPathVar && route.constraint != nil:
+		return varKey(route.path) + string(textutils.ColonChar) + route.constraint.expr
+	case route.isPathVar:
+		return varKey(route.path)
+	}
+	return route.path
+}
+
+//addSubRoute adds the sub route, the constrained path variables are matched in the order they are added and before
+//the unconstrained one. It panics if the sub route is ambiguous with an existing one.
+func (route *Route) addSubRoute(sub *Route) {
+	if route.conflict(sub) != nil {
+		panic("one path cannot have multiple names")
+	}
+	if sub.isCatchAll {
+		route.catchAllName = sub.path
+	} else if sub.isPathVar {
+		idx := len(route.varRoutes)
+		for i, v := range route.varRoutes {
+			if v.constraint == nil && idx > i {
+				idx = i
+			}
+		}
+		varRoutes := make([]*Route, 0, len(route.varRoutes)+1)
+		varRoutes = append(varRoutes, route.varRoutes[:idx]...)
+		varRoutes = append(varRoutes, sub)
+		route.varRoutes = append(varRoutes, route.varRoutes[idx:]...)
+	}
+	route.subRoutes[sub.key()] = sub
+}
+
+//conflict returns the sub route matching the same segments as the new sub route under another name if any, a catch-all
+//variable or an unconstrained variable conflicts with any other and a constrained one with the same constraint
+func (route *Route) conflict(sub *Route) *Route {
+	if sub.isCatchAll {
+		if route.catchAllName != textutils.EmptyStr {
+			return route.subRoutes[catchAllKey(route.catchAllName)]
+		}
+	} else if sub.isPathVar {
+		for _, v := range route.varRoutes {
+			if (v.constraint == nil && sub.constraint == nil) ||
+				(v.constraint != nil && sub.constraint != nil && v.constraint.expr == sub.constraint.expr) {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+//matches checks if the segment of the request path matches the route
+func (route *Route) matches(segment string) bool {
+	switch {
+	case route.isWildcard:
+		return segment != textutils.EmptyStr
+	case route.isPathVar:
+		return segment != textutils.EmptyStr && (route.constraint == nil || route.constraint.match(segment))
+	}
+	return segment == route.path
+}
+
+//schema describes the values of the path variable in the OAS
+func (route *Route) schema() *spec.Schema {
+	if route.constraint != nil {
+		schema := *route.constraint.schema
+		return &schema
+	}
+	return &spec.Schema{Type: "string"}
+}
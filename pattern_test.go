@@ -0,0 +1,398 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//paramHandler writes the value of the path param
+func paramHandler(router *Router, name string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		value, err := router.GetPathParams(name, r)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(name + "=" + value))
+	}
+}
+
+func TestRouter_Wildcards(t *testing.T) {
+	router := NewRouter()
+	router.Get("/files/{path...}", paramHandler(router, "path"))
+	router.Get("/files/readme", testHandler)
+	router.Get("/proxy/*", paramHandler(router, "*"))
+	router.Get("/teams/*/posts", testHandler)
+	router.Get("/teams/core/posts", paramHandler(router, "team"))
+	router.Get("/users/{id}/files/{path...}", paramHandler(router, "path"))
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "catch-all",
+			path:   "/files/docs/api/index.html",
+			status: http.StatusOK,
+			body:   "path=docs/api/index.html",
+		},
+		{
+			name:   "catch-all single segment",
+			path:   "/files/notes.txt",
+			status: http.StatusOK,
+			body:   "path=notes.txt",
+		},
+		{
+			name:   "catch-all empty",
+			path:   "/files/",
+			status: http.StatusOK,
+			body:   "path=",
+		},
+		{
+			name:   "static takes priority over catch-all",
+			path:   "/files/readme",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "catch-all below a static segment",
+			path:   "/files/readme/old",
+			status: http.StatusOK,
+			body:   "path=readme/old",
+		},
+		{
+			name:   "trailing wildcard",
+			path:   "/proxy/v1/users/42",
+			status: http.StatusOK,
+			body:   "*=v1/users/42",
+		},
+		{
+			name:   "single segment wildcard",
+			path:   "/teams/search/posts",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "static takes priority over wildcard",
+			path:   "/teams/core/posts",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "wildcard matches one segment only",
+			path:   "/teams/a/b/posts",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "catch-all after a path variable",
+			path:   "/users/42/files/a/b",
+			status: http.StatusOK,
+			body:   "path=a/b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(GET, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_Constraints(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id:int}", paramHandler(router, "id"))
+	router.Get("/users/{slug}", paramHandler(router, "slug"))
+	router.Get("/users/{uid:uuid}", paramHandler(router, "uid"))
+	router.Get("/items/{sku:[A-Z]{3}-\\d+}", paramHandler(router, "sku"))
+	router.Get("/prices/:amount:float", paramHandler(router, "amount"))
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "typed constraint",
+			path:   "/users/42",
+			status: http.StatusOK,
+			body:   "id=42",
+		},
+		{
+			name:   "constraint checked before the unconstrained variable",
+			path:   "/users/3f2b8c1e-6d4a-4b8e-9f1a-2c3d4e5f6a7b",
+			status: http.StatusOK,
+			body:   "uid=3f2b8c1e-6d4a-4b8e-9f1a-2c3d4e5f6a7b",
+		},
+		{
+			name:   "unconstrained alternative",
+			path:   "/users/jane",
+			status: http.StatusOK,
+			body:   "slug=jane",
+		},
+		{
+			name:   "regex constraint",
+			path:   "/items/ABC-123",
+			status: http.StatusOK,
+			body:   "sku=ABC-123",
+		},
+		{
+			name:   "regex constraint matches the whole segment",
+			path:   "/items/ABC-123x",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "regex constraint not satisfied",
+			path:   "/items/abc-123",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "constraint with the : prefix",
+			path:   "/prices/9.99",
+			status: http.StatusOK,
+			body:   "amount=9.99",
+		},
+		{
+			name:   "typed constraint not satisfied",
+			path:   "/prices/free",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(GET, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+
+	oas := router.OpenAPI(spec.Info{Title: "constraints", Version: "1.0.0"})
+	params := oas.Paths["/users/{id}"].Get.Parameters
+	if len(params) != 1 || params[0].Schema.Type != "integer" {
+		t.Errorf("OpenAPI() parameters of /users/{id} = %v, want an integer", params)
+	}
+	params = oas.Paths["/items/{sku}"].Get.Parameters
+	if len(params) != 1 || params[0].Schema.Pattern == nil || *params[0].Schema.Pattern != "[A-Z]{3}-\\d+" {
+		t.Errorf("OpenAPI() parameters of /items/{sku} = %v, want the pattern", params)
+	}
+}
+
+func TestRouter_Backtracking(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/me", testHandler)
+	router.Get("/users/me/settings", testHandler)
+	router.Get("/users/{id}", paramHandler(router, "id"))
+	router.Get("/users/{id}/posts", paramHandler(router, "id"))
+	router.Get("/orders/{id:int}/items", paramHandler(router, "id"))
+	router.Get("/orders/{ref}/invoice", paramHandler(router, "ref"))
+	router.Get("/orders/*/history", testHandler)
+	router.Get("/orders/{path...}", paramHandler(router, "path"))
+	router.Get("/teams/core/members/lead", testHandler)
+	router.Get("/teams/{team}/members", paramHandler(router, "team"))
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "static sibling of a path variable",
+			path:   "/users/me",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "path variable sibling of a static segment",
+			path:   "/users/42",
+			status: http.StatusOK,
+			body:   "id=42",
+		},
+		{
+			name:   "static below the static segment",
+			path:   "/users/me/settings",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "backtrack from the static segment to the path variable",
+			path:   "/users/me/posts",
+			status: http.StatusOK,
+			body:   "id=me",
+		},
+		{
+			name:   "constrained path variable",
+			path:   "/orders/42/items",
+			status: http.StatusOK,
+			body:   "id=42",
+		},
+		{
+			name:   "backtrack from the constrained to the plain path variable",
+			path:   "/orders/42/invoice",
+			status: http.StatusOK,
+			body:   "ref=42",
+		},
+		{
+			name:   "backtrack to the wildcard",
+			path:   "/orders/42/history",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "backtrack to the catch-all",
+			path:   "/orders/42/items/7",
+			status: http.StatusOK,
+			body:   "path=42/items/7",
+		},
+		{
+			name:   "route with a handler preferred over an intermediate route",
+			path:   "/teams/core/members",
+			status: http.StatusOK,
+			body:   "team=core",
+		},
+		{
+			name:   "no match",
+			path:   "/users/me/posts/1",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(GET, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_RootVariables(t *testing.T) {
+	router := NewRouter()
+	router.Get("/{tenant}/orders", paramHandler(router, "tenant"))
+	router.Get("/{tenant}/orders/{id:int}", paramHandler(router, "id"))
+	router.Get("/health", testHandler)
+	router.Get("/admin/orders", testHandler)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "root variable",
+			path:   "/acme/orders",
+			status: http.StatusOK,
+			body:   "tenant=acme",
+		},
+		{
+			name:   "root variable with a nested variable",
+			path:   "/acme/orders/42",
+			status: http.StatusOK,
+			body:   "id=42",
+		},
+		{
+			name:   "static top level route takes priority",
+			path:   "/health",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "static top level route sharing the rest of the path",
+			path:   "/admin/orders",
+			status: http.StatusOK,
+			body:   "testHandler",
+		},
+		{
+			name:   "backtrack from the static top level route",
+			path:   "/health/orders",
+			status: http.StatusOK,
+			body:   "tenant=health",
+		},
+		{
+			name:   "no match",
+			path:   "/acme/invoices",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(GET, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_AddPatternPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{
+			name:  "catch-all not last",
+			paths: []string{"/files/{path...}/meta"},
+		},
+		{
+			name:  "catch-all without name",
+			paths: []string{"/files/{...}"},
+		},
+		{
+			name:  "catch-all with different names",
+			paths: []string{"/files/{path...}", "/files/{rest...}"},
+		},
+		{
+			name:  "path variables with different names",
+			paths: []string{"/users/{id}", "/users/{name}/posts"},
+		},
+		{
+			name:  "constrained variables with the same constraint",
+			paths: []string{"/users/{id:int}", "/users/{num:int}"},
+		},
+		{
+			name:  "invalid regex constraint",
+			paths: []string{"/items/{sku:[A-Z}"},
+		},
+		{
+			name:  "catch-all with a constraint",
+			paths: []string{"/files/{path...:[a-z]+}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Add(%v) did not panic", tt.paths)
+				}
+			}()
+			for _, path := range tt.paths {
+				router.Get(path, testHandler)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package turbo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//TrailingSlashPolicy defines how the router handles the paths that only differ from a route by a trailing slash
+type TrailingSlashPolicy int
+
+const (
+	//TrailingSlashStrict treats /users and /users/ as different paths, this is the default policy.
+	TrailingSlashStrict TrailingSlashPolicy = iota
+	//TrailingSlashRedirect redirects the path to the route with or without the trailing slash.
+	TrailingSlashRedirect
+	//TrailingSlashIgnore serves the path with the route with or without the trailing slash.
+	TrailingSlashIgnore
+)
+
+//SetTrailingSlash sets the policy for the paths that do not match any route but would match one with the trailing
+//slash added or removed. The routes registered with and without the trailing slash are served as is.
+func (router *Router) SetTrailingSlash(policy TrailingSlashPolicy) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.trailingSlash = policy
+	return router
+}
+
+//SetRedirectCode sets the status code of the redirects to the canonical path, 301 Moved Permanently by default.
+//Only http.StatusMovedPermanently and http.StatusPermanentRedirect are supported, the clients following a 308
+//Permanent Redirect send the request again with the same method and body.
+func (router *Router) SetRedirectCode(code int) *Router {
+	if code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect {
+		panic(fmt.Sprintf("Invalid/Unsupported redirect status code %d provided", code))
+	}
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.redirectCode = code
+	return router
+}
+
+//redirect returns the handler sending the client to the path keeping the query of the request, it is called with the
+//lock held
+func (router *Router) redirect(path string) http.Handler {
+	code := router.redirectCode
+	if code == 0 {
+		code = http.StatusMovedPermanently
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		url := *r.URL
+		url.Path = path
+		url.RawPath = ""
+		location := url.String()
+		w.Header().Set("Location", location)
+		w.WriteHeader(code)
+		_, err := w.Write([]byte("Path Moved : " + location + "\n"))
+		if err != nil {
+			logger.Error(err)
+		}
+	})
+}
+
+//toggleTrailingSlash adds the trailing slash to the path or removes it
+func toggleTrailingSlash(path string) string {
+	if strings.HasSuffix(path, PathSeparator) {
+		return strings.TrimSuffix(path, PathSeparator)
+	}
+	return path + PathSeparator
+}
@@ -0,0 +1,155 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_RootPath(t *testing.T) {
+	router := NewRouter()
+	router.Get("/", testHandler)
+	router.Post("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("created"))
+	})
+	router.Get("/{tenant}", paramHandler(router, "tenant"))
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{method: GET, path: "/", status: http.StatusOK, body: "testHandler"},
+		{method: POST, path: "/", status: http.StatusOK, body: "created"},
+		{method: PUT, path: "/", status: http.StatusMethodNotAllowed},
+		{method: GET, path: "/acme", status: http.StatusOK, body: "tenant=acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+
+	empty := NewRouter()
+	w := httptest.NewRecorder()
+	empty.ServeHTTP(w, httptest.NewRequest(GET, "/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status without a root route = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRouter_SetTrailingSlash(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   TrailingSlashPolicy
+		code     int
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{
+			name:   "strict",
+			policy: TrailingSlashStrict,
+			path:   "/users/",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "strict exact path",
+			policy: TrailingSlashStrict,
+			path:   "/posts/",
+			status: http.StatusOK,
+		},
+		{
+			name:     "redirect removes the trailing slash",
+			policy:   TrailingSlashRedirect,
+			path:     "/users/?page=2",
+			status:   http.StatusMovedPermanently,
+			location: "/users?page=2",
+		},
+		{
+			name:     "redirect adds the trailing slash",
+			policy:   TrailingSlashRedirect,
+			path:     "/posts",
+			status:   http.StatusMovedPermanently,
+			location: "/posts/",
+		},
+		{
+			name:     "redirect with 308",
+			policy:   TrailingSlashRedirect,
+			code:     http.StatusPermanentRedirect,
+			method:   POST,
+			path:     "/users/",
+			status:   http.StatusPermanentRedirect,
+			location: "/users",
+		},
+		{
+			name:   "redirect keeps the routes registered with both forms",
+			policy: TrailingSlashRedirect,
+			path:   "/teams/",
+			status: http.StatusOK,
+		},
+		{
+			name:   "redirect without a route",
+			policy: TrailingSlashRedirect,
+			path:   "/orders/",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "ignore",
+			policy: TrailingSlashIgnore,
+			path:   "/users/42/",
+			status: http.StatusOK,
+		},
+		{
+			name:     "clean path with 308",
+			policy:   TrailingSlashStrict,
+			code:     http.StatusPermanentRedirect,
+			path:     "/users//42",
+			status:   http.StatusPermanentRedirect,
+			location: "/users/42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter().SetTrailingSlash(tt.policy)
+			if tt.code != 0 {
+				router.SetRedirectCode(tt.code)
+			}
+			router.Add("/users", testHandler, GET, POST)
+			router.Get("/users/{id}", testHandler)
+			router.Get("/posts/", testHandler)
+			router.Get("/teams", testHandler)
+			router.Get("/teams/", testHandler)
+			method := tt.method
+			if method == "" {
+				method = GET
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("Location = %v, want %v", location, tt.location)
+			}
+		})
+	}
+}
+
+func TestRouter_SetRedirectCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetRedirectCode(%d) did not panic", http.StatusFound)
+		}
+	}()
+	NewRouter().SetRedirectCode(http.StatusFound)
+}
@@ -0,0 +1,78 @@
+package turbo
+
+import (
+	"net/http"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+)
+
+//Remove removes the handlers of the methods from the route registered at the path, or all its handlers if no method
+//is given. The path is the template the route was added with such as /users/{id:int}. The routes left without
+//handlers and sub routes are removed from the router. It returns false if the path has no handler for the methods.
+//The routes can be removed while the router serves requests, the requests being served complete with the handler
+//they started with.
+func (router *Router) Remove(path string, methods ...string) bool {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	if router.root == nil {
+		return false
+	}
+	routes := []*Route{router.root}
+	segments := splitPath(strings.TrimSpace(path))
+	for i, segment := range segments {
+		next, ok := routes[i].subRoutes[newSegmentRoute(segment, i == len(segments)-1).key()]
+		if !ok {
+			return false
+		}
+		routes = append(routes, next)
+	}
+	route := routes[len(routes)-1]
+	if len(methods) == 0 {
+		for method := range route.handlers {
+			methods = append(methods, method)
+		}
+	}
+	removed := false
+	for _, method := range methods {
+		if _, ok := route.handlers[method]; ok {
+			delete(route.handlers, method)
+			delete(route.groups, method)
+			delete(route.operations, method)
+			removed = true
+		}
+	}
+	//the routes are pruned from the end of the path while they have neither handlers nor sub routes
+	for i := len(routes) - 1; i > 0; i-- {
+		if len(routes[i].handlers) > 0 || len(routes[i].subRoutes) > 0 {
+			break
+		}
+		routes[i-1].removeSubRoute(routes[i])
+	}
+	return removed
+}
+
+//Replace sets the handler of the methods at the path in place of the current ones, the variants of the methods added
+//with AddVariant are discarded while the filters, the authenticator and the query params of the route are kept.
+//The route is added if it does not exist. The requests being served complete with the handler they started with.
+func (router *Router) Replace(path string, f func(w http.ResponseWriter, r *http.Request), methods ...string) *Route {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	return router.add(path, f, true, nil, methods)
+}
+
+//removeSubRoute removes the sub route and its path variable
+func (route *Route) removeSubRoute(sub *Route) {
+	delete(route.subRoutes, sub.key())
+	if sub.isCatchAll {
+		route.catchAllName = textutils.EmptyStr
+	} else if sub.isPathVar {
+		varRoutes := make([]*Route, 0, len(route.varRoutes))
+		for _, v := range route.varRoutes {
+			if v != sub {
+				varRoutes = append(varRoutes, v)
+			}
+		}
+		route.varRoutes = varRoutes
+	}
+}
@@ -0,0 +1,243 @@
+package turbo
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestRouter_Remove(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		methods []string
+		want    bool
+		method  string
+		request string
+		status  int
+		body    string
+	}{
+		{
+			name:    "one method",
+			path:    "/users",
+			methods: []string{POST},
+			want:    true,
+			method:  POST,
+			request: "/users",
+			status:  http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "other method kept",
+			path:    "/users",
+			methods: []string{POST},
+			want:    true,
+			request: "/users",
+			status:  http.StatusOK,
+			body:    "users:GET",
+		},
+		{
+			name:    "sub routes kept",
+			path:    "/users",
+			want:    true,
+			request: "/users/jane",
+			status:  http.StatusOK,
+			body:    "user:GET",
+		},
+		{
+			name:    "constrained variable",
+			path:    "/users/{id:int}",
+			want:    true,
+			request: "/users/42",
+			status:  http.StatusOK,
+			body:    "user:GET",
+		},
+		{
+			name:    "catch-all variable",
+			path:    "/files/{path...}",
+			want:    true,
+			request: "/files/css/site.css",
+			status:  http.StatusNotFound,
+		},
+		{
+			name:    "root",
+			path:    "/",
+			want:    true,
+			request: "/",
+			status:  http.StatusNotFound,
+		},
+		{
+			name:    "root variable",
+			path:    "/{tenant}/info",
+			want:    true,
+			request: "/acme/info",
+			status:  http.StatusNotFound,
+		},
+		{
+			name:    "unknown path",
+			path:    "/teams",
+			request: "/users/42",
+			status:  http.StatusOK,
+			body:    "id:GET",
+		},
+		{
+			name:    "method not registered",
+			path:    "/users",
+			methods: []string{PUT},
+			request: "/users",
+			status:  http.StatusOK,
+			body:    "users:GET",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter()
+			router.Get("/", methodHandler("root"))
+			router.Add("/users", methodHandler("users"), GET, POST)
+			router.Get("/users/{id:int}", methodHandler("id"))
+			router.Get("/users/{name}", methodHandler("user"))
+			router.Get("/files/{path...}", methodHandler("files"))
+			router.Get("/{tenant}/info", methodHandler("info"))
+			if got := router.Remove(tt.path, tt.methods...); got != tt.want {
+				t.Errorf("Remove() = %v, want %v", got, tt.want)
+			}
+			method := tt.method
+			if method == "" {
+				method = GET
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(method, tt.request, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouter_RemovePrunes(t *testing.T) {
+	router := NewRouter()
+	router.Get("/teams/{team}/members/{member}", testHandler)
+	router.Get("/teams/{team}/projects/*", testHandler)
+	router.Remove("/teams/{team}/members/{member}")
+	router.Remove("/teams/{team}/projects/*")
+	if routes := router.Routes(); len(routes) != 0 {
+		t.Errorf("Routes() = %+v, want none", routes)
+	}
+	//the removed variables no longer conflict with the ones of another name
+	router.Get("/teams/{name}/projects/{path...}", testHandler)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(GET, "/teams/core/projects/turbo/issues", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestRouter_Replace(t *testing.T) {
+	router := NewRouter()
+	router.Get("/reports", methodHandler("v1")).
+		AddFilter(traceFilter("route")).
+		AddQueryParam("year", true).
+		AddVariant(GET, methodHandler("csv"), Accept("text/csv"))
+	router.Replace("/reports", methodHandler("v2"), GET)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "handler replaced",
+			path:   "/reports?year=2020",
+			status: http.StatusOK,
+			body:   "v2:GET",
+		},
+		{
+			name:   "query params kept",
+			path:   "/reports",
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(GET, tt.path, nil)
+			r.Header.Set("Accept", "text/csv")
+			router.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %v, want %v", w.Code, tt.status)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("body = %v, want %v", w.Body.String(), tt.body)
+			}
+			if got := w.Header().Get("X-Trace"); tt.status == http.StatusOK && got != "route" {
+				t.Errorf("X-Trace = %v, want route", got)
+			}
+		})
+	}
+}
+
+//TestRouter_ChangesWhileServing changes the routes while they are served, run with -race to detect the data races
+func TestRouter_ChangesWhileServing(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id:int}", methodHandler("user"))
+	router.Get("/feature", methodHandler("feature"))
+	api := router.Group("/api")
+	api.AddAuthenticator(headerAuthenticator("token"))
+	done := make(chan struct{})
+	changed := make(chan struct{})
+	go func() {
+		defer close(changed)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			plugin := "/plugins/" + strconv.Itoa(i%10) + "/status"
+			router.Get(plugin, methodHandler("plugin")).
+				AddFilter(traceFilter("plugin")).
+				AddQueryParam("verbose", false).
+				AddVariant(GET, methodHandler("json"), Accept("application/json")).
+				Name(fmt.Sprintf("plugin.%d", i))
+			router.Replace("/feature", methodHandler("feature"+strconv.Itoa(i)), GET)
+			if i%2 == 0 {
+				router.Remove("/feature")
+			}
+			router.Remove(plugin)
+			api.Get("/private", methodHandler("private"))
+			router.Remove("/api/private")
+			router.Routes()
+		}
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				for _, path := range []string{"/users/42", "/feature", "/plugins/7/status"} {
+					w := httptest.NewRecorder()
+					router.ServeHTTP(w, httptest.NewRequest(GET, path, nil))
+					if w.Code != http.StatusOK && w.Code != http.StatusNotFound && w.Code != http.StatusMethodNotAllowed {
+						t.Errorf("%s status = %v", path, w.Code)
+					}
+				}
+				//the routes of the group are never served without its authenticator
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(GET, "/api/private", nil))
+				if w.Code == http.StatusOK {
+					t.Errorf("/api/private served without the token")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	<-changed
+}
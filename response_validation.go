@@ -0,0 +1,163 @@
+package turbo
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//ResponseValidationMode defines how the router handles the responses that do not match the OAS operation of the route
+type ResponseValidationMode int
+
+const (
+	//ResponseValidationOff does not validate the responses, this is the default mode.
+	ResponseValidationOff ResponseValidationMode = iota
+	//ResponseValidationLog logs the violations through the logger of the route and sends the response as is.
+	ResponseValidationLog
+	//ResponseValidationStrict logs the violations and replaces the response with a 500 listing the violations.
+	ResponseValidationStrict
+)
+
+//SetResponseValidation sets the mode for validating the status code, the headers and the JSON body written by the
+//handlers against the responses of the OAS operations set on the routes.
+//The responses are buffered till the handler returns, this mode is meant for the staging and test environments.
+func (router *Router) SetResponseValidation(mode ResponseValidationMode) *Router {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	router.responseValidation = mode
+	return router
+}
+
+//responseRecorder buffers the response written by the handler so that it can be validated before sending it
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(data)
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+//validateResponses wraps the handler to validate its responses against the operation
+func (route *Route) validateResponses(op *spec.Operation, mode ResponseValidationMode, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{header: make(http.Header)}
+		handler.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		errs := validateResponse(op, rec)
+		if len(errs) > 0 {
+			l := route.logger
+			if l == nil {
+				l = logger
+			}
+			for _, err := range errs {
+				l.ErrorF("Response validation failed for %s %s %s", r.Method, r.URL.Path, err)
+			}
+			if mode == ResponseValidationStrict {
+				w.Header().Set("Content-Type", ContentTypeJSON)
+				w.WriteHeader(http.StatusInternalServerError)
+				if err := json.NewEncoder(w).Encode(&ValidationFailure{
+					Message: "response validation failed",
+					Errors:  errs,
+				}); err != nil {
+					logger.Error(err)
+				}
+				return
+			}
+		}
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.status)
+		if _, err := w.Write(rec.body.Bytes()); err != nil {
+			logger.Error(err)
+		}
+	})
+}
+
+//validateResponse validates the recorded response against the responses of the operation
+func validateResponse(op *spec.Operation, rec *responseRecorder) []*spec.ValidationError {
+	if len(op.Responses) == 0 {
+		return nil
+	}
+	response := findResponse(op.Responses, rec.status)
+	if response == nil {
+		return []*spec.ValidationError{{Pointer: "/status", Message: "status " + strconv.Itoa(rec.status) + " is not documented"}}
+	}
+	if response.Ref != nil {
+		return nil
+	}
+	var errs []*spec.ValidationError
+	for name, header := range response.Headers {
+		pointer := "/header/" + name
+		value := rec.header.Get(name)
+		if value == textutils.EmptyStr {
+			if header.Required {
+				errs = append(errs, &spec.ValidationError{Pointer: pointer, Message: "required header " + name + " is missing"})
+			}
+			continue
+		}
+		if header.Schema != nil {
+			errs = append(errs, header.Schema.ValidateValue(coerceValue(value, header.Schema), pointer)...)
+		}
+	}
+	const pointer = "/body"
+	if rec.body.Len() == 0 || len(response.Content) == 0 {
+		return errs
+	}
+	contentType := rec.header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = textutils.EmptyStr
+	}
+	content, ok := findContent(response.Content, mediaType)
+	if !ok {
+		return append(errs, &spec.ValidationError{Pointer: pointer, Message: "content type " + contentType + " is not documented"})
+	}
+	if content.Schema == nil || !isJSON(mediaType) {
+		return errs
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(rec.body.Bytes()))
+	decoder.UseNumber()
+	if err = decoder.Decode(&v); err != nil {
+		return append(errs, &spec.ValidationError{Pointer: pointer, Message: "invalid JSON: " + err.Error()})
+	}
+	return append(errs, content.Schema.ValidateValue(v, pointer)...)
+}
+
+//findResponse finds the response for the status code falling back to the range (2XX) and the default responses
+func findResponse(responses map[string]*spec.Response, status int) *spec.Response {
+	code := strconv.Itoa(status)
+	if response, ok := responses[code]; ok {
+		return response
+	}
+	for key, response := range responses {
+		if len(key) == 3 && key[0] == code[0] && strings.ToUpper(key[1:]) == "XX" {
+			return response
+		}
+	}
+	return responses["default"]
+}
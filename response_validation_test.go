@@ -0,0 +1,84 @@
+package turbo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func TestRouter_SetResponseValidation(t *testing.T) {
+	op := &spec.Operation{
+		OperationID: "getPet",
+		Responses: map[string]*spec.Response{
+			"200": {
+				Description: "the pet",
+				Headers: map[string]spec.Header{
+					"X-Rate-Limit": {Required: true, Schema: &spec.Schema{Type: spec.TypeInteger}},
+				},
+				Content: map[string]spec.MediaType{
+					ContentTypeJSON: {Schema: &spec.Schema{
+						Type:       spec.TypeObject,
+						Required:   []string{"name"},
+						Properties: map[string]*spec.Schema{"name": {Type: spec.TypeString}},
+					}},
+				},
+			},
+			"4XX": {Description: "client errors"},
+		},
+	}
+	handler := func(status int, rate, body string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if rate != "" {
+				w.Header().Set("X-Rate-Limit", rate)
+			}
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	tests := []struct {
+		name       string
+		mode       ResponseValidationMode
+		status     int
+		rate       string
+		body       string
+		want       int
+		wantErrors int
+	}{
+		{"Valid", ResponseValidationStrict, http.StatusOK, "10", `{"name": "rex"}`, http.StatusOK, 0},
+		{"Range", ResponseValidationStrict, http.StatusNotFound, "", `{}`, http.StatusNotFound, 0},
+		{"Status", ResponseValidationStrict, http.StatusCreated, "10", `{"name": "rex"}`, http.StatusInternalServerError, 1},
+		{"Body", ResponseValidationStrict, http.StatusOK, "abc", `{"name": 1}`, http.StatusInternalServerError, 2},
+		{"Header", ResponseValidationStrict, http.StatusOK, "", `{"name": "rex"}`, http.StatusInternalServerError, 1},
+		{"Log", ResponseValidationLog, http.StatusCreated, "10", `{"name": 1}`, http.StatusCreated, 0},
+		{"Off", ResponseValidationOff, http.StatusCreated, "10", `{"name": 1}`, http.StatusCreated, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter().SetResponseValidation(tt.mode)
+			router.Get("/pets/{id}", handler(tt.status, tt.rate, tt.body)).SetOperation(GET, op)
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(GET, "/pets/1", nil)
+			router.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("ServeHTTP() got = %v, want = %v", w.Code, tt.want)
+			}
+			if tt.want != http.StatusInternalServerError {
+				if w.Body.String() != tt.body {
+					t.Errorf("ServeHTTP() body = %v, want = %v", w.Body.String(), tt.body)
+				}
+				return
+			}
+			var failure ValidationFailure
+			if err := json.NewDecoder(w.Body).Decode(&failure); err != nil {
+				t.Fatal(err)
+			}
+			if len(failure.Errors) != tt.wantErrors {
+				t.Errorf("ServeHTTP() errors = %v, want %v", failure.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
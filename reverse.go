@@ -0,0 +1,108 @@
+package turbo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+)
+
+//Name names the route so that its path can be built with Router.URL
+func (route *Route) Name(name string) *Route {
+	if strings.TrimSpace(name) == textutils.EmptyStr {
+		panic("the name of the route cannot be empty")
+	}
+	return route.update(func() {
+		route.name = name
+	})
+}
+
+//URL builds the path of the route named with Route.Name from its template, the values of the path variables are
+//given as name and value pairs such as URL("user.detail", "id", "42"). Every variable of the template has to be given
+//a value satisfying its constraint and only them. The values are escaped, the slashes of the value of a catch-all
+//variable are kept as is.
+func (router *Router) URL(name string, pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return textutils.EmptyStr, errors.New(fmt.Sprintf("odd number of name and value pairs for the route %s", name))
+	}
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+	router.lock.RLock()
+	var chains [][]*Route
+	for _, route := range router.topLevelRoutes {
+		chains = route.named(name, nil, chains)
+	}
+	router.lock.RUnlock()
+	switch len(chains) {
+	case 0:
+		return textutils.EmptyStr, errors.New(fmt.Sprintf("no route named %s", name))
+	case 1:
+	default:
+		return textutils.EmptyStr, errors.New(fmt.Sprintf("%d routes named %s", len(chains), name))
+	}
+	var sb strings.Builder
+	used := 0
+	for _, route := range chains[0] {
+		sb.WriteString(PathSeparator)
+		switch {
+		case route.isWildcard:
+			return textutils.EmptyStr, errors.New(fmt.Sprintf("the path of the route %s has a wildcard segment", name))
+		case route.isPathVar:
+			value, ok := values[route.path]
+			if !ok {
+				return textutils.EmptyStr, errors.New(fmt.Sprintf("missing value of the path variable %s of the route %s", route.path, name))
+			}
+			used++
+			if route.isCatchAll {
+				segments := strings.Split(value, PathSeparator)
+				for i := range segments {
+					segments[i] = url.PathEscape(segments[i])
+				}
+				sb.WriteString(strings.Join(segments, PathSeparator))
+				continue
+			}
+			if !route.matches(value) {
+				return textutils.EmptyStr, errors.New(fmt.Sprintf("invalid value %s of the path variable %s of the route %s", value, route.path, name))
+			}
+			sb.WriteString(url.PathEscape(value))
+		default:
+			sb.WriteString(route.path)
+		}
+	}
+	if used != len(values) {
+		for key := range values {
+			if !hasVar(chains[0], key) {
+				return textutils.EmptyStr, errors.New(fmt.Sprintf("unknown path variable %s of the route %s", key, name))
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+//named appends the chains of routes from the top level route to the routes with the name
+func (route *Route) named(name string, parents []*Route, chains [][]*Route) [][]*Route {
+	chain := make([]*Route, 0, len(parents)+1)
+	chain = append(chain, parents...)
+	chain = append(chain, route)
+	if route.name == name {
+		chains = append(chains, chain)
+	}
+	for _, sub := range route.subRoutes {
+		chains = sub.named(name, chain, chains)
+	}
+	return chains
+}
+
+//hasVar checks if one of the routes is the path variable
+func hasVar(chain []*Route, name string) bool {
+	for _, route := range chain {
+		if route.isPathVar && route.path == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package turbo
+
+import (
+	"testing"
+)
+
+func TestRouter_URL(t *testing.T) {
+	router := NewRouter()
+	router.Get("/", testHandler).Name("home")
+	router.Get("/users/{id:int}", testHandler).Name("user.detail")
+	router.Get("/users/{id:int}/posts/{slug}", testHandler).Name("user.post")
+	router.Get("/files/{path...}", testHandler).Name("file")
+	router.Get("/teams/*/members", testHandler).Name("members")
+	router.Get("/{tenant}/orders/", testHandler).Name("orders")
+	router.Group("/api/v1").Get("/status", testHandler).Name("status")
+	router.Get("/dup/a", testHandler).Name("dup")
+	router.Get("/dup/b", testHandler).Name("dup")
+
+	tests := []struct {
+		name    string
+		route   string
+		pairs   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "root", route: "home", want: "/"},
+		{name: "static", route: "status", want: "/api/v1/status"},
+		{name: "variable", route: "user.detail", pairs: []string{"id", "42"}, want: "/users/42"},
+		{
+			name:  "variables",
+			route: "user.post",
+			pairs: []string{"slug", "hello world/again", "id", "7"},
+			want:  "/users/7/posts/hello%20world%2Fagain",
+		},
+		{name: "catch-all", route: "file", pairs: []string{"path", "docs/a b.txt"}, want: "/files/docs/a%20b.txt"},
+		{name: "trailing slash and root variable", route: "orders", pairs: []string{"tenant", "acme"}, want: "/acme/orders/"},
+		{name: "constraint not satisfied", route: "user.detail", pairs: []string{"id", "me"}, wantErr: true},
+		{name: "missing variable", route: "user.post", pairs: []string{"id", "7"}, wantErr: true},
+		{name: "unknown variable", route: "user.detail", pairs: []string{"id", "7", "page", "2"}, wantErr: true},
+		{name: "odd pairs", route: "user.detail", pairs: []string{"id"}, wantErr: true},
+		{name: "unknown route", route: "user.list", wantErr: true},
+		{name: "duplicate name", route: "dup", wantErr: true},
+		{name: "wildcard", route: "members", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := router.URL(tt.route, tt.pairs...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("URL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("URL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
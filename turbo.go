@@ -1,10 +1,10 @@
 package turbo
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"go.nandlabs.io/l3"
+	"go.nandlabs.io/turbo/api/spec"
 	"go.nandlabs.io/turbo/auth"
 	"net/http"
 	"strconv"
@@ -23,6 +23,30 @@ type Router struct {
 	unsupportedMethodHandler http.Handler
 	//Routes Managed by this router
 	topLevelRoutes map[string]*Route
+	//root holds the path variables at the root context, its sub routes are the topLevelRoutes
+	root *Route
+	//responseValidation mode for validating the responses against the OAS operations of the routes
+	responseValidation ResponseValidationMode
+	//trailingSlash policy for the paths matching a route only with the trailing slash added or removed
+	trailingSlash TrailingSlashPolicy
+	//redirectCode is the status code of the redirects to the canonical path, 301 if not set
+	redirectCode int
+	//autoHead answers HEAD with the GET handler of the route
+	autoHead bool
+	//autoOptions answers OPTIONS with the Allow header listing the methods of the route
+	autoOptions bool
+	//allowHeader adds the Allow header to the 405 Method Not Allowed responses
+	allowHeader bool
+	//methods accepted by this router in addition to the Methods
+	methods map[string]bool
+	//hosts served by their own router, ordered by the number of variables of the host
+	hosts []*host
+	//filters added with Use wrapping the whole dispatch
+	filters []FilterFunc
+	//dispatcher is the dispatch wrapped with the filters
+	dispatcher http.Handler
+	//mounts serving the paths under their prefix, ordered by the longest prefix first
+	mounts []*mount
 }
 
 //Param to hold key value
@@ -36,12 +60,18 @@ type Route struct {
 	//name of the route fragment if this is a path variable the name of the variable will be used here.
 	path      string
 	paramType string
-	//Checks if this is a variable. only one path variable at this level will be supported.
+	//Checks if this is a variable. only one unconstrained path variable at this level will be supported.
 	isPathVar bool
-	//childVarName varName
-	childVarName string
-	//hasChildVar
-	hasChildVar bool
+	//isCatchAll checks if this is a variable capturing the rest of the path, slashes included
+	isCatchAll bool
+	//isWildcard checks if this is a * matching any single segment without capturing it
+	isWildcard bool
+	//constraint the value of the path variable has to satisfy if any
+	constraint *constraint
+	//varRoutes are the path variables of the sub routes in the order they are matched
+	varRoutes []*Route
+	//catchAllName is the name of the catch-all variable of the sub routes if any
+	catchAllName string
 	//isAuthenticated keeps a check whether the route is authenticated or not
 	authFilter auth.Authenticator
 	//filters array to store the ...http.handler being registered for middleware in the router
@@ -54,6 +84,16 @@ type Route struct {
 	queryParams map[string]*QueryParam
 	//logger to set the external logger if required using SetLogger()
 	logger *l3.BaseLogger
+	//operations describing the handlers <method>|<OAS Operation>
+	operations map[string]*spec.Operation
+	//groups through which the handlers were registered <method>|<Group>
+	groups map[string]*Group
+	//name of the route for building its path with Router.URL
+	name string
+	//lock of the router the route is registered on, guarding the changes made while the router serves requests
+	lock *sync.RWMutex
+	//router the route is registered on
+	router *Router
 }
 
 //QueryParam for the Route configuration
@@ -68,12 +108,26 @@ type QueryParam struct {
 //NewRouter registers the new instance of the Turbo Framework
 func NewRouter() *Router {
 	logger.InfoF("Initiating Turbo")
-	return &Router{
+	router := &Router{
 		lock:                     sync.RWMutex{},
 		unManagedRouteHandler:    endpointNotFoundHandler(),
 		unsupportedMethodHandler: methodNotAllowedHandler(),
 		topLevelRoutes:           make(map[string]*Route),
 	}
+	router.root = newRootRoute(router.topLevelRoutes)
+	router.autoHead = true
+	router.autoOptions = true
+	router.allowHeader = true
+	return router
+}
+
+//newRootRoute creates the route of the root context having the top level routes as sub routes
+func newRootRoute(topLevelRoutes map[string]*Route) *Route {
+	return &Route{
+		handlers:    make(map[string]http.Handler),
+		subRoutes:   topLevelRoutes,
+		queryParams: make(map[string]*QueryParam),
+	}
 }
 
 //Get to Add a turbo handler for GET method
@@ -96,116 +150,155 @@ func (router *Router) Delete(path string, f func(w http.ResponseWriter, r *http.
 	return router.Add(path, f, DELETE)
 }
 
+//Any to Add a turbo handler for every HTTP method, the handlers added for specific methods on the same path take
+//precedence
+func (router *Router) Any(path string, f func(w http.ResponseWriter, r *http.Request)) *Route {
+	return router.Add(path, f, anyMethod)
+}
+
 //Add a turbo handler for one or more HTTP methods.
 func (router *Router) Add(path string, f func(w http.ResponseWriter, r *http.Request), methods ...string) *Route {
 	router.lock.Lock()
 	defer router.lock.Unlock()
-	var route *Route = nil
+	return router.add(path, f, false, nil, methods)
+}
+
+//add registers the handler for the methods, with replace the variants of the methods are discarded. The handlers
+//registered through a group are bound to it before the lock is released so that they are never served without the
+//filters and the authenticator of the group.
+func (router *Router) add(path string, f func(w http.ResponseWriter, r *http.Request), replace bool, group *Group, methods []string) *Route {
 	//Check if the methods provided are valid if not return error straight away
 	for _, method := range methods {
-		if _, contains := Methods[method]; !contains {
+		if !router.supports(method) {
 			panic(fmt.Sprintf("Invalid/Unsupported Http method  %s provided", method))
 		}
 	}
 	logger.InfoF("Registering New Route: %s", path)
-	//TODO add path check for any query variables specified.
-	pathValue := strings.TrimSpace(path)
-
-	//Adds support to path with variables in {} format instead of : prefix
-	var sb strings.Builder
-	for _, c := range pathValue {
-		if c == textutils.OpenBraceChar {
-			sb.WriteRune(textutils.ColonChar)
-		} else if c == textutils.CloseBraceChar {
-			logger.Debug("Ignoring char ", textutils.CloseBraceStr)
-		} else {
-			sb.WriteRune(c)
+	route := router.route(path)
+	//At Last index add the method(s) to the map.
+	for _, method := range methods {
+		route.setHandler(method, prepareHandler(method, http.HandlerFunc(f)), replace)
+	}
+	if group != nil {
+		if route.groups == nil {
+			route.groups = make(map[string]*Group)
+		}
+		for _, method := range methods {
+			route.groups[method] = group
 		}
 	}
-	pathValue = sb.String()
+	return route
+}
 
-	pathValues := strings.Split(pathValue, PathSeparator)[1:]
+//route returns the route of the path, the routes of the segments that are not registered yet are added
+func (router *Router) route(path string) *Route {
+	var route *Route = nil
+	if router.root == nil {
+		router.root = newRootRoute(router.topLevelRoutes)
+	}
+	//TODO add path check for any query variables specified.
+	pathValue := strings.TrimSpace(path)
+
+	//path variables are supported in {} format as well as with the : prefix
+	pathValues := splitPath(pathValue)
 	length := len(pathValues)
 	if length > 0 && pathValues[0] != textutils.EmptyStr {
-		isPathVar := false
-		name := textutils.EmptyStr
 		for i, pathValue := range pathValues {
-			isPathVar = pathValue[0] == textutils.ColonChar
-			if isPathVar {
-				name = pathValue[1:]
-			} else {
-				name = pathValue
-			}
-			currentRoute := &Route{
-				path:         name,
-				isPathVar:    isPathVar,
-				childVarName: textutils.EmptyStr,
-				hasChildVar:  false,
-				authFilter:   nil,
-				logger:       logger,
-				handlers:     make(map[string]http.Handler),
-				subRoutes:    make(map[string]*Route),
-				queryParams:  make(map[string]*QueryParam),
-			}
+			currentRoute := newSegmentRoute(pathValue, i == len(pathValues)-1)
+			currentRoute.logger = logger
+			currentRoute.lock = &router.lock
+			currentRoute.router = router
+			//No Parent present the root holds the top level routes and the path variables at root context
 			if route == nil {
-				if v, ok := router.topLevelRoutes[name]; ok {
-					route = v
-				} else {
-					//No Parent present add the current route as route and continue
-					if currentRoute.isPathVar {
-						panic("the framework does not support path variables at root context")
-					}
-					router.topLevelRoutes[name] = currentRoute
-					route = currentRoute
-				}
-			} else {
-				if v, ok := route.subRoutes[name]; ok {
-					if v.isPathVar && isPathVar && v.path != name {
-						panic("one path cannot have multiple names")
-					}
-					route = v
-				} else {
-					route.subRoutes[name] = currentRoute
-					if isPathVar {
-						route.childVarName = name
-						route.hasChildVar = true
-					}
-					route = currentRoute
-				}
+				route = router.root
 			}
-			//At Last index add the method(s) to the map.
-			if i == len(pathValues)-1 {
-				for _, method := range methods {
-					currentRoute.handlers[method] = http.HandlerFunc(f)
-				}
+			if v, ok := route.subRoutes[currentRoute.key()]; ok {
+				route = v
+			} else {
+				route.addSubRoute(currentRoute)
+				route = currentRoute
 			}
 		}
 	} else {
-		//TODO Handle the Root context path
-		currentRoute := &Route{
-			path:         textutils.EmptyStr,
-			isPathVar:    false,
-			childVarName: textutils.EmptyStr,
-			handlers:     make(map[string]http.Handler),
-			subRoutes:    make(map[string]*Route),
-			queryParams:  make(map[string]*QueryParam),
-			authFilter:   nil,
-			logger:       logger,
-		}
-		for _, method := range methods {
-			currentRoute.handlers[method] = prepareHandler(method, http.HandlerFunc(f))
-		}
 		//Root route will not have any path value
-		router.topLevelRoutes[textutils.EmptyStr] = currentRoute
+		if v, ok := router.topLevelRoutes[textutils.EmptyStr]; ok {
+			route = v
+		} else {
+			route = newSegmentRoute(textutils.EmptyStr, true)
+			route.logger = logger
+			route.lock = &router.lock
+			route.router = router
+			router.topLevelRoutes[textutils.EmptyStr] = route
+		}
 	}
 	return route
 }
 
+//check returns an error if the path cannot be added to the routes of the router, the routes are left as is
+func (router *Router) check(path string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(fmt.Sprintf("%s: %v", path, r))
+		}
+	}()
+	route := router.root
+	if route == nil {
+		route = newRootRoute(router.topLevelRoutes)
+	}
+	pathValues := splitPath(strings.TrimSpace(path))
+	if len(pathValues) == 0 || pathValues[0] == textutils.EmptyStr {
+		return nil
+	}
+	for i, pathValue := range pathValues {
+		//the segments are created for their validation even below a route that is not registered yet
+		currentRoute := newSegmentRoute(pathValue, i == len(pathValues)-1)
+		if route == nil {
+			continue
+		}
+		if v, ok := route.subRoutes[currentRoute.key()]; ok {
+			route = v
+			continue
+		}
+		if c := route.conflict(currentRoute); c != nil {
+			return errors.New(fmt.Sprintf("%s: the path variable %s conflicts with the path variable %s", path, currentRoute.path, c.path))
+		}
+		route = nil
+	}
+	return nil
+}
+
 //prepareHandler to add any default features like logging, auth... will be injected here
 func prepareHandler(method string, handler http.Handler) http.Handler {
 	return handler
 }
 
+//setHandler sets the handler of the method, the variants of the method added with AddVariant are kept unless replaced
+func (route *Route) setHandler(method string, handler http.Handler, replace bool) {
+	if variants, ok := route.handlers[method].(*variantHandler); ok && !replace {
+		route.handlers[method] = &variantHandler{variants: variants.variants, fallback: handler}
+		return
+	}
+	route.handlers[method] = handler
+}
+
+//update applies the change to the route under the lock of its router so that it is safe while serving requests
+func (route *Route) update(change func()) *Route {
+	if route.lock != nil {
+		route.lock.Lock()
+		defer route.lock.Unlock()
+	}
+	change()
+	return route
+}
+
+//AddQueryParam declares a query parameter for the route, requests missing a required query parameter are rejected
+//with 400 Bad Request before the handler is invoked.
+func (route *Route) AddQueryParam(name string, required bool) *Route {
+	return route.update(func() {
+		route.addQueryVar(name, required)
+	})
+}
+
 //addQueryVar to add query params to the route
 func (route *Route) addQueryVar(name string, required bool) *Route {
 	//TODO add name validation.
@@ -214,42 +307,139 @@ func (route *Route) addQueryVar(name string, required bool) *Route {
 		name:     name,
 	}
 	//TODO Check if this name can be url encoded and save decoding per request,
-	route.queryParams[name] = queryParams
+	//the query params are copied as the handlers being served keep the ones they were created with
+	newQueryParams := make(map[string]*QueryParam, len(route.queryParams)+1)
+	for k, v := range route.queryParams {
+		newQueryParams[k] = v
+	}
+	newQueryParams[name] = queryParams
+	route.queryParams = newQueryParams
 	return route
 }
 
-// ServeHTTP
+//requireQueryParams wraps the handler to reject the requests that do not have the required query params
+func (route *Route) requireQueryParams(handler http.Handler) http.Handler {
+	queryParams := route.queryParams
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for name, q := range queryParams {
+			if _, ok := query[name]; q.required && !ok {
+				queryParamMissing(w, r, name)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP dispatches the request through the filters of the router.
+// The configuration and the routes of the router are read under a single read lock per request and the handler is
+// served after releasing it, so that the routes can be changed while the requests are served. The router filters
+// added with Use are served outside the lock, the routes are then read under a second one.
 func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router.lock.RLock()
+	if dispatcher := router.dispatcher; dispatcher != nil {
+		router.lock.RUnlock()
+		dispatcher.ServeHTTP(w, r)
+		return
+	}
+	handler, req := router.resolve(w, r)
+	router.lock.RUnlock()
+	handler.ServeHTTP(w, req)
+}
+
+//dispatch finds the route of the request and serves it through the handler chain of the route
+func (router *Router) dispatch(w http.ResponseWriter, r *http.Request) {
+	router.lock.RLock()
+	handler, req := router.resolve(w, r)
+	router.lock.RUnlock()
+	handler.ServeHTTP(w, req)
+}
+
+//resolve finds the route of the request and builds its handler chain with the request to pass to it, the requests
+//for a host are passed to the router of the host. It is called with the lock held.
+func (router *Router) resolve(w http.ResponseWriter, r *http.Request) (http.Handler, *http.Request) {
+	// the requests for a host are served by the router of the host
+	if hostRouter, req := router.findHost(r); hostRouter != nil {
+		return hostRouter, req
+	}
+	r = connectTarget(r)
 	path := r.URL.Path
-	var handler http.Handler
 	// perform the path checks before, set the 301 status even before further computation
 	// these checks need not be performed once the PreWork is refined and up to the mark
 	if p := refinePath(path); p != path {
-		url := *r.URL
-		url.Path = p
-		p = url.String()
-		w.Header().Set("Location", p)
-		w.WriteHeader(http.StatusMovedPermanently)
-		_, err := w.Write([]byte("Path Moved : " + p + "\n"))
-		if err != nil {
-			logger.Error(err)
-		}
-		return
+		return router.redirect(p), r
 	}
+	var handler http.Handler
 	// start by checking where the method of the Request is same as that of the registered method
 	match, params := router.findRoute(r)
+	// the path with the trailing slash added or removed is tried as per the trailing slash policy
+	if (match == nil || len(match.handlers) == 0) && router.trailingSlash != TrailingSlashStrict && path != PathSeparator {
+		alternate := toggleTrailingSlash(path)
+		if altMatch, altParams := router.matchPath(alternate); altMatch != nil && len(altMatch.handlers) > 0 {
+			if router.trailingSlash == TrailingSlashRedirect {
+				return router.redirect(alternate), r
+			}
+			match, params = altMatch, altParams
+		}
+	}
+	// the paths that do not match any route are passed to the mount of their prefix if any
+	if match == nil || len(match.handlers) == 0 {
+		if mounted, req := router.findMount(r); mounted != nil {
+			return mounted, req
+		}
+	}
 	if match != nil {
-		handler = match.handlers[r.Method]
-		if len(match.filters) > 0 {
+		method := r.Method
+		// HEAD is served by the GET handler with the body suppressed unless a HEAD handler is registered
+		autoHead := method == HEAD && router.autoHead && match.handlers[HEAD] == nil && match.handlers[GET] != nil
+		if autoHead {
+			method = GET
+		}
+		// the handler registered with Any serves the methods that do not have a handler
+		if match.handlers[method] == nil && match.handlers[anyMethod] != nil {
+			method = anyMethod
+		}
+		handler = match.handlers[method]
+		if handler != nil && len(match.queryParams) > 0 {
+			handler = match.requireQueryParams(handler)
+		}
+		if handler != nil && router.responseValidation != ResponseValidationOff {
+			if op := match.operations[method]; op != nil {
+				handler = match.validateResponses(op, router.responseValidation, handler)
+			}
+		}
+		if handler != nil && len(match.filters) > 0 {
 			//Middlewares added
 			for i := range match.filters {
 				handler = match.filters[len(match.filters)-1-i](handler)
 			}
 		}
+		// the filters of the group are executed before the filters of the route
+		group := match.groups[method]
+		if handler != nil && group != nil {
+			handler = group.apply(handler)
+		}
 		// check for authenticated filter explicitly at the top
 		// we add all the filters added by the user in its order and if the user has added an Authenticator Filter then it will always be executed first
-		if match.authFilter != nil {
+		// the authenticator of the route takes precedence over the one of its group
+		if handler != nil && match.authFilter != nil {
 			handler = match.authFilter.Apply(handler)
+		} else if handler != nil && group != nil {
+			if authFilter := group.authenticator(); authFilter != nil {
+				handler = authFilter.Apply(handler)
+			}
+		}
+		if handler != nil && autoHead {
+			handler = suppressBody(handler)
+		}
+		if handler == nil && r.Method == OPTIONS && router.autoOptions && len(match.handlers) > 0 {
+			handler = router.optionsHandler(match)
+		}
+		if handler == nil && router.allowHeader {
+			if allowed := router.allowedMethods(match); len(allowed) > 0 {
+				w.Header().Set(AllowHeader, strings.Join(allowed, ", "))
+			}
 		}
 	} else {
 		handler = router.unManagedRouteHandler
@@ -257,53 +447,77 @@ func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if handler == nil {
 		handler = router.unsupportedMethodHandler
 	}
-	if params != nil {
-		r = r.WithContext(context.WithValue(r.Context(), "params", params))
-	}
-	handler.ServeHTTP(w, r)
+	return handler, withParams(r, params)
 }
 
 // findRoute performs the function checks for the incoming request path whether it matches with any registered route's path
+// the static segments take priority over the constrained path variables, the unconstrained path variable, the wildcard
+// and the catch-all variable in that order. When the rest of the path does not match below a route the next one is tried.
 func (router *Router) findRoute(req *http.Request) (*Route, []Param) {
-	var route *Route
-	var params []Param = nil
-	pathLen := len(req.URL.Path)
-	prevIdx := 1
-	lastIdx := false
-	for idx := 1; idx < pathLen; idx++ {
-		lastIdx = idx == pathLen-1
-		if req.URL.Path[idx] == textutils.ForwardSlashChar || lastIdx {
-			if lastIdx {
-				idx++
-			}
-			val := req.URL.Path[prevIdx:idx]
-			prevIdx = idx + 1
-			if route == nil {
-				route = router.topLevelRoutes[val]
-				continue
-			} else {
-				if route.hasChildVar {
-					route = route.subRoutes[route.childVarName]
-				} else {
-					if r, ok := route.subRoutes[val]; ok {
-						route = r
-					} else {
-						return nil, nil
-					}
-				}
-				if route.isPathVar {
-					if params == nil {
-						params = []Param{}
-					}
-					params = append(params, Param{
-						key:   route.path,
-						value: val,
-					})
-				}
+	return router.matchPath(req.URL.Path)
+}
+
+//matchPath finds the route matching the path, the root path / matches the route registered at the root context
+func (router *Router) matchPath(path string) (*Route, []Param) {
+	if path == textutils.EmptyStr {
+		return nil, nil
+	}
+	root := router.root
+	if root == nil {
+		root = newRootRoute(router.topLevelRoutes)
+	}
+	//the routes having a handler are preferred over the intermediate routes of the path
+	if match, params := root.find(path, 1, nil, true); match != nil {
+		return match, params
+	}
+	return root.find(path, 1, nil, false)
+}
+
+//nextSegment returns the segment of the path starting at the index and the index of the segment after it
+func nextSegment(path string, start int) (string, int) {
+	end := strings.IndexByte(path[start:], textutils.ForwardSlashChar)
+	if end < 0 {
+		return path[start:], len(path) + 1
+	}
+	return path[start : start+end], start + end + 1
+}
+
+//find matches the rest of the path starting at the index below the route, with handled the route matching the whole
+//path has to have a handler
+func (route *Route) find(path string, start int, params []Param, handled bool) (*Route, []Param) {
+	if start > len(path) {
+		if handled && len(route.handlers) == 0 {
+			return nil, nil
+		}
+		return route, params
+	}
+	val, next := nextSegment(path, start)
+	if r, ok := route.subRoutes[val]; ok && !r.isPathVar && !r.isWildcard {
+		if match, matchParams := r.find(path, next, params, handled); match != nil {
+			return match, matchParams
+		}
+	}
+	for _, r := range route.varRoutes {
+		if r.matches(val) {
+			//the params are copied so that the alternatives tried do not share the backing array
+			varParams := append(params[:len(params):len(params)], Param{key: r.path, value: val})
+			if match, matchParams := r.find(path, next, varParams, handled); match != nil {
+				return match, matchParams
 			}
 		}
 	}
-	return route, params
+	if r, ok := route.subRoutes[wildcard]; ok && r.matches(val) {
+		if match, matchParams := r.find(path, next, params, handled); match != nil {
+			return match, matchParams
+		}
+	}
+	if route.catchAllName != textutils.EmptyStr {
+		r := route.subRoutes[catchAllKey(route.catchAllName)]
+		if !handled || len(r.handlers) > 0 {
+			return r, append(params[:len(params):len(params)], Param{key: r.path, value: path[start:]})
+		}
+	}
+	return nil, nil
 }
 
 //GetPathParams fetches the path parameters
@@ -7,7 +7,6 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
-	"sync"
 	"testing"
 )
 
@@ -41,12 +40,11 @@ func TestRouter_findRoute(t *testing.T) {
 		req *http.Request
 	}
 	route := &Route{
-		path:         "abc",
-		isPathVar:    false,
-		childVarName: "",
-		handlers:     make(map[string]http.Handler),
-		subRoutes:    make(map[string]*Route),
-		queryParams:  nil,
+		path:        "abc",
+		isPathVar:   false,
+		handlers:    make(map[string]http.Handler),
+		subRoutes:   make(map[string]*Route),
+		queryParams: nil,
 	}
 	tlr["abc"] = route
 	testUrl, _ := url.Parse("/abc")
@@ -857,7 +855,6 @@ func TestRouter_Get(t *testing.T) {
 
 func TestRouter_Add(t *testing.T) {
 	type fields struct {
-		lock                     sync.RWMutex
 		unManagedRouteHandler    http.Handler
 		unsupportedMethodHandler http.Handler
 		topLevelRoutes           map[string]*Route
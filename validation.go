@@ -0,0 +1,225 @@
+package turbo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.nandlabs.io/commons/textutils"
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+//Parameter locations as per https://spec.openapis.org/oas/v3.1.0#parameter-locations
+const (
+	InPath   = "path"
+	InQuery  = "query"
+	InHeader = "header"
+	InCookie = "cookie"
+)
+
+//ContentTypeJSON is the media type of the JSON documents
+const ContentTypeJSON = "application/json"
+
+//ValidationFailure is the JSON body of the 400 response sent when a request fails the validation
+type ValidationFailure struct {
+	Message string                  `json:"message"`
+	Errors  []*spec.ValidationError `json:"errors"`
+}
+
+//ValidationFilter creates a filter that validates the requests against the parameters and the request body of the
+//operation before the handler is invoked. All the violations are reported in a single 400 response with a
+//ValidationFailure body. Violations are located with the pointers /<in>/<name> for the parameters and /body for the
+//request body. The operation is expected to be resolved with spec.OAS.Dereference i.e. without any $ref.
+func ValidationFilter(op *spec.Operation) FilterFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			errs := validateParameters(op.Parameters, r)
+			if op.RequestBody != nil {
+				bodyErrs, err := validateBody(op.RequestBody, r)
+				if err != nil {
+					logger.ErrorF("Error reading the request body: %v", err)
+				}
+				errs = append(errs, bodyErrs...)
+			}
+			if len(errs) > 0 {
+				writeValidationFailure(w, errs)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+//validateParameters validates the request parameters as per their schemas
+func validateParameters(parameters []*spec.Parameter, r *http.Request) []*spec.ValidationError {
+	var errs []*spec.ValidationError
+	params, _ := r.Context().Value("params").([]Param)
+	for _, p := range parameters {
+		if p == nil || p.Ref != nil {
+			continue
+		}
+		pointer := PathSeparator + p.In + PathSeparator + p.Name
+		values, present := parameterValues(p, r, params)
+		if !present {
+			if p.Required || p.In == InPath {
+				errs = append(errs, &spec.ValidationError{Pointer: pointer, Message: "required " + p.In + " parameter " + p.Name + " is missing"})
+			}
+			continue
+		}
+		if p.Schema != nil {
+			errs = append(errs, p.Schema.ValidateValue(coerce(values, p), pointer)...)
+			continue
+		}
+		//Parameters with a content map are serialized as per the media type
+		for mediaType, content := range p.Content {
+			if content.Schema != nil && isJSON(mediaType) {
+				var v interface{}
+				if err := json.Unmarshal([]byte(values[0]), &v); err != nil {
+					errs = append(errs, &spec.ValidationError{Pointer: pointer, Message: "invalid JSON: " + err.Error()})
+				} else {
+					errs = append(errs, content.Schema.ValidateValue(v, pointer)...)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+//parameterValues fetches the raw values of the parameter from the request
+func parameterValues(p *spec.Parameter, r *http.Request, params []Param) ([]string, bool) {
+	switch p.In {
+	case InPath:
+		for _, param := range params {
+			if param.key == p.Name {
+				return []string{param.value}, true
+			}
+		}
+	case InQuery:
+		values, ok := r.URL.Query()[p.Name]
+		return values, ok
+	case InHeader:
+		values := r.Header.Values(p.Name)
+		return values, len(values) > 0
+	case InCookie:
+		if c, err := r.Cookie(p.Name); err == nil {
+			return []string{c.Value}, true
+		}
+	}
+	return nil, false
+}
+
+//coerce converts the raw parameter values to the JSON types of the parameter schema.
+//The values that cannot be converted are retained as strings and reported by the schema validation.
+func coerce(values []string, p *spec.Parameter) interface{} {
+	if p.Schema.Type == spec.TypeArray {
+		if len(values) == 1 && (!p.Exploded() || p.In == InPath || p.In == InHeader) {
+			values = strings.Split(values[0], p.Delimiter())
+		}
+		items := make([]interface{}, len(values))
+		for i, v := range values {
+			items[i] = coerceValue(v, p.Schema.Items)
+		}
+		return items
+	}
+	return coerceValue(values[0], p.Schema)
+}
+
+//coerceValue converts a single raw value to the JSON type of the schema
+func coerceValue(value string, schema *spec.Schema) interface{} {
+	if schema == nil {
+		return value
+	}
+	switch schema.Type {
+	case spec.TypeInteger, spec.TypeNumber:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case spec.TypeBoolean:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+//validateBody validates the JSON request bodies against the schema of their media type.
+//The body is restored on the request so that it can be read by the handler.
+func validateBody(body *spec.RequestBody, r *http.Request) ([]*spec.ValidationError, error) {
+	const pointer = "/body"
+	var data []byte
+	if r.Body != nil {
+		var err error
+		if data, err = ioutil.ReadAll(r.Body); err != nil {
+			return []*spec.ValidationError{{Pointer: pointer, Message: "unable to read the request body"}}, err
+		}
+		_ = r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+	if len(data) == 0 {
+		if body.Required {
+			return []*spec.ValidationError{{Pointer: pointer, Message: "request body is required"}}, nil
+		}
+		return nil, nil
+	}
+	if len(body.Content) == 0 {
+		return nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = textutils.EmptyStr
+	}
+	content, ok := findContent(body.Content, mediaType)
+	if !ok {
+		return []*spec.ValidationError{{Pointer: pointer, Message: "unsupported content type " + r.Header.Get("Content-Type")}}, nil
+	}
+	if content.Schema == nil || !isJSON(mediaType) {
+		return nil, nil
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&v); err != nil {
+		return []*spec.ValidationError{{Pointer: pointer, Message: "invalid JSON: " + err.Error()}}, nil
+	}
+	return content.Schema.ValidateValue(v, pointer), nil
+}
+
+//findContent finds the media type object for the media type, falling back to the type/* and */* ranges
+func findContent(content map[string]spec.MediaType, mediaType string) (spec.MediaType, bool) {
+	if c, ok := content[mediaType]; ok {
+		return c, true
+	}
+	if i := strings.Index(mediaType, PathSeparator); i > 0 {
+		if c, ok := content[mediaType[:i]+"/*"]; ok {
+			return c, true
+		}
+	}
+	c, ok := content["*/*"]
+	return c, ok
+}
+
+//isJSON checks if the media type is application/json or a +json structured syntax suffix
+func isJSON(mediaType string) bool {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+//writeValidationFailure writes the 400 response listing all the violations
+func writeValidationFailure(w http.ResponseWriter, errs []*spec.ValidationError) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	w.WriteHeader(http.StatusBadRequest)
+	err := json.NewEncoder(w).Encode(&ValidationFailure{
+		Message: "request validation failed",
+		Errors:  errs,
+	})
+	if err != nil {
+		logger.Error(err)
+	}
+}
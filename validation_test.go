@@ -0,0 +1,147 @@
+package turbo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nandlabs.io/turbo/api/spec"
+)
+
+func TestValidationFilter(t *testing.T) {
+	max, minLen := 100.0, 3
+	op := &spec.Operation{
+		Parameters: []*spec.Parameter{
+			{Name: "id", In: InPath, Required: true, Schema: &spec.Schema{Type: spec.TypeInteger}},
+			{Name: "limit", In: InQuery, Schema: &spec.Schema{Type: spec.TypeInteger, Maximum: &max}},
+			{Name: "tags", In: InQuery, Schema: &spec.Schema{Type: spec.TypeArray, Items: &spec.Schema{Type: spec.TypeString, MinLength: &minLen}}},
+			{Name: "X-Tenant", In: InHeader, Required: true, Schema: &spec.Schema{Type: spec.TypeString}},
+			{Name: "session", In: InCookie, Schema: &spec.Schema{Type: spec.TypeString, Enum: []interface{}{"a", "b"}}},
+		},
+		RequestBody: &spec.RequestBody{
+			Required: true,
+			Content: map[string]spec.MediaType{
+				ContentTypeJSON: {Schema: &spec.Schema{Type: spec.TypeObject, Required: []string{"name"}}},
+			},
+		},
+	}
+	var body string
+	router := NewRouter()
+	router.Post("/api/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data := make([]byte, 64)
+		n, _ := r.Body.Read(data)
+		body = string(data[:n])
+	}).AddFilter(ValidationFilter(op))
+
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		contentType string
+		cookie      string
+		tenant      string
+		want        int
+		wantErrors  []string
+	}{
+		{
+			name:        "Valid",
+			path:        "/api/items/42?limit=10&tags=abc,defg",
+			body:        `{"name": "foo"}`,
+			contentType: "application/json; charset=utf-8",
+			cookie:      "a",
+			tenant:      "t1",
+			want:        http.StatusOK,
+		},
+		{
+			name:        "Invalid",
+			path:        "/api/items/abc?limit=500&tags=ab",
+			body:        `{}`,
+			contentType: ContentTypeJSON,
+			cookie:      "c",
+			want:        http.StatusBadRequest,
+			wantErrors: []string{
+				"/path/id: expected type integer but found string",
+				"/query/limit: value must be <= 100",
+				"/query/tags/0: length must be >= 3",
+				"/header/X-Tenant: required header parameter X-Tenant is missing",
+				"/cookie/session: value must be one of [a b]",
+				"/body: required property name is missing",
+			},
+		},
+		{
+			name:        "MissingBody",
+			path:        "/api/items/1",
+			contentType: ContentTypeJSON,
+			tenant:      "t1",
+			want:        http.StatusBadRequest,
+			wantErrors:  []string{"/body: request body is required"},
+		},
+		{
+			name:        "ContentType",
+			path:        "/api/items/1",
+			body:        "name=foo",
+			contentType: "application/x-www-form-urlencoded",
+			tenant:      "t1",
+			want:        http.StatusBadRequest,
+			wantErrors:  []string{"/body: unsupported content type application/x-www-form-urlencoded"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body = ""
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(POST, tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.tenant != "" {
+				r.Header.Set("X-Tenant", tt.tenant)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+			router.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("ServeHTTP() got = %v, want = %v, body = %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK {
+				if body != tt.body {
+					t.Errorf("handler body = %v, want = %v", body, tt.body)
+				}
+				return
+			}
+			var failure ValidationFailure
+			if err = json.NewDecoder(w.Body).Decode(&failure); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range failure.Errors {
+				got = append(got, e.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.wantErrors, "\n") {
+				t.Errorf("ValidationFilter() errors = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestRouter_ServeHTTPRequiredQueryParam(t *testing.T) {
+	router := NewRouter()
+	router.Get("/api/search", dummyHandler).AddQueryParam("q", true).AddQueryParam("page", false)
+	for path, want := range map[string]int{
+		"/api/search?q=turbo":        http.StatusOK,
+		"/api/search?q=":             http.StatusOK,
+		"/api/search?page=1":         http.StatusBadRequest,
+		"/api/search?page=1&q=turbo": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(GET, path, nil)
+		router.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("ServeHTTP() %s got = %v, want = %v", path, w.Code, want)
+		}
+	}
+}
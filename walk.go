@@ -0,0 +1,115 @@
+package turbo
+
+import (
+	"sort"
+
+	"go.nandlabs.io/commons/textutils"
+)
+
+//RouteInfo describes a route served by the router
+type RouteInfo struct {
+	//Path is the template of the path such as /users/{id:int}/files/{path...}
+	Path string
+	//Name of the route set with Route.Name
+	Name string
+	//Methods having a handler sorted by name, * stands for the handler added with Any
+	Methods []string
+	//Variables are the names of the path variables in the order of the path
+	Variables []string
+	//QueryParams declared with Route.AddQueryParam sorted by name
+	QueryParams []QueryParamInfo
+	//Filters is the number of filters added to the route, the filters of the groups and of the router are not counted
+	Filters int
+	//Authenticated is true if the route or one of the groups it was added through has an authenticator
+	Authenticated bool
+}
+
+//QueryParamInfo describes a query param declared on a route
+type QueryParamInfo struct {
+	Name     string
+	Required bool
+}
+
+//Walk calls the function for each route having a handler in the order of their paths, the walk stops at the first
+//error returned by the function which is returned. The routes of the hosts and of the mounted handlers are not walked.
+func (router *Router) Walk(fn func(info RouteInfo) error) error {
+	for _, info := range router.Routes() {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Routes returns the routes having a handler in the order of their paths
+func (router *Router) Routes() []RouteInfo {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	var infos []RouteInfo
+	for _, key := range sortedRouteKeys(router.topLevelRoutes) {
+		infos = router.topLevelRoutes[key].walk(textutils.EmptyStr, nil, infos)
+	}
+	return infos
+}
+
+//walk appends the info of the route and of its sub routes
+func (route *Route) walk(parent string, variables []string, infos []RouteInfo) []RouteInfo {
+	path := parent + PathSeparator + route.template()
+	if route.isPathVar {
+		vars := make([]string, 0, len(variables)+1)
+		vars = append(vars, variables...)
+		variables = append(vars, route.path)
+	}
+	if len(route.handlers) > 0 {
+		info := RouteInfo{
+			Path:          path,
+			Name:          route.name,
+			Variables:     variables,
+			Filters:       len(route.filters),
+			Authenticated: route.authFilter != nil,
+		}
+		for method := range route.handlers {
+			info.Methods = append(info.Methods, method)
+			if group := route.groups[method]; group != nil && group.authenticator() != nil {
+				info.Authenticated = true
+			}
+		}
+		sort.Strings(info.Methods)
+		for _, q := range route.queryParams {
+			info.QueryParams = append(info.QueryParams, QueryParamInfo{Name: q.name, Required: q.required})
+		}
+		sort.Slice(info.QueryParams, func(i, j int) bool {
+			return info.QueryParams[i].Name < info.QueryParams[j].Name
+		})
+		infos = append(infos, info)
+	}
+	for _, key := range sortedRouteKeys(route.subRoutes) {
+		infos = route.subRoutes[key].walk(path, variables, infos)
+	}
+	return infos
+}
+
+//template is the segment of the path template of the route
+func (route *Route) template() string {
+	switch {
+	case route.isCatchAll && route.path == wildcard:
+		return wildcard
+	case route.isCatchAll:
+		return textutils.OpenBraceStr + route.path + catchAllSuffix + textutils.CloseBraceStr
+	case route.isPathVar && route.constraint != nil:
+		return textutils.OpenBraceStr + route.path + string(textutils.ColonChar) + route.constraint.expr + textutils.CloseBraceStr
+	case route.isPathVar:
+		return textutils.OpenBraceStr + route.path + textutils.CloseBraceStr
+	}
+	return route.path
+}
+
+//sortedRouteKeys returns the keys of the routes sorted
+func sortedRouteKeys(routes map[string]*Route) []string {
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package turbo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_Routes(t *testing.T) {
+	router := NewRouter()
+	router.Get("/", testHandler)
+	router.Add("/users", testHandler, GET, POST).AddQueryParam("page", false).AddQueryParam("size", true)
+	router.Get("/users/{id:int}", testHandler).Name("user.detail").AddFilter(dummyFilter, dummyFilter)
+	router.Get("/users/{id:int}/files/{path...}", testHandler).AddAuthenticator(headerAuthenticator("token"))
+	router.Any("/proxy/*", testHandler)
+	admin := router.Group("/admin")
+	admin.AddAuthenticator(headerAuthenticator("token"))
+	admin.Delete("/cache", testHandler)
+
+	want := []RouteInfo{
+		{Path: "/", Methods: []string{GET}},
+		{Path: "/admin/cache", Methods: []string{DELETE}, Authenticated: true},
+		{Path: "/proxy/*", Methods: []string{anyMethod}, Variables: []string{"*"}},
+		{
+			Path:        "/users",
+			Methods:     []string{GET, POST},
+			QueryParams: []QueryParamInfo{{Name: "page"}, {Name: "size", Required: true}},
+		},
+		{
+			Path:      "/users/{id:int}",
+			Name:      "user.detail",
+			Methods:   []string{GET},
+			Variables: []string{"id"},
+			Filters:   2,
+		},
+		{
+			Path:          "/users/{id:int}/files/{path...}",
+			Methods:       []string{GET},
+			Variables:     []string{"id", "path"},
+			Authenticated: true,
+		},
+	}
+	if got := router.Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v, want %+v", got, want)
+	}
+
+	var paths []string
+	stop := errors.New("stop")
+	err := router.Walk(func(info RouteInfo) error {
+		if info.Path == "/proxy/*" {
+			return stop
+		}
+		paths = append(paths, info.Path)
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Walk() error = %v, want %v", err, stop)
+	}
+	if !reflect.DeepEqual(paths, []string{"/", "/admin/cache"}) {
+		t.Errorf("Walk() paths = %v", paths)
+	}
+}